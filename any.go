@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// AnyWith使用的序列化函数，未设置时为nil
+var anyEncoder atomic.Pointer[func(interface{}) ([]byte, error)]
+
+// AnyWith 任意类型，使用WithAnyEncoder设置的序列化函数输出为json，例如protojson，
+// 未设置时使用encoding/json，只有日志输出时才序列化
+func AnyWith(key string, val interface{}) Field {
+	return zap.Reflect(key, anyWith{val: val})
+}
+
+type anyWith struct {
+	val interface{}
+}
+
+func (a anyWith) MarshalJSON() ([]byte, error) {
+	if fn := anyEncoder.Load(); fn != nil {
+		return (*fn)(a.val)
+	}
+	return json.Marshal(a.val)
+}
+
+// SortedAny 任意类型，map类型(包括嵌套的map)按key排序输出，每次输出的字段顺序一致，方便快照测试，
+// 非map类型同Any
+func SortedAny(key string, val interface{}) Field {
+	if v := reflect.ValueOf(val); v.Kind() == reflect.Map && !v.IsNil() {
+		return zap.Object(key, sortedMap{v: v})
+	}
+	return Any(key, val)
+}
+
+type sortedMap struct {
+	v reflect.Value
+}
+
+func (m sortedMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	type entry struct {
+		key string
+		val reflect.Value
+	}
+	entries := make([]entry, 0, m.v.Len())
+	iter := m.v.MapRange()
+	for iter.Next() {
+		entries = append(entries, entry{key: fmt.Sprint(iter.Key().Interface()), val: iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	for _, e := range entries {
+		val := e.val
+		if val.Kind() == reflect.Interface {
+			val = val.Elem()
+		}
+		if val.Kind() == reflect.Map && !val.IsNil() {
+			if err := enc.AddObject(e.key, sortedMap{v: val}); err != nil {
+				return err
+			}
+			continue
+		}
+		if !val.IsValid() {
+			if err := enc.AddReflected(e.key, nil); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.AddReflected(e.key, val.Interface()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Any序列化后的字节数上限，由WithLargeAnyWarning设置，0表示不检查
+var largeAnyLimit atomic.Int64
+
+// Any的值序列化后超过limit字节时，每个调用位置只输出一次warn级别日志，日志的调用位置为Any的调用位置
+func warnLargeAny(key string, val interface{}, limit int) {
+	data, err := json.Marshal(val)
+	if err != nil || len(data) <= limit {
+		return
+	}
+
+	_, file, line, _ := runtime.Caller(2)
+	if !firstTime(fmt.Sprintf("large_any:%s:%d", file, line)) {
+		return
+	}
+	loadLogger().WithOptions(zap.AddCallerSkip(2)).Warn("large value logged with Any, consider logging only the needed fields",
+		String("any_key", key), Int("any_size", len(data)), Int("any_limit", limit))
+}
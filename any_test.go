@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAnyWith(t *testing.T) {
+	encoder := func(v interface{}) ([]byte, error) {
+		if p, ok := v.(*people); ok {
+			return json.Marshal(map[string]interface{}{"full_name": p.Name, "age": p.Age})
+		}
+		return nil, errors.New("unsupported type")
+	}
+	filename := initTestLogger(t, WithAnyEncoder(encoder))
+	Info("any with", AnyWith("people", &people{"张三", 11}), AnyWith("bad", 1))
+
+	entry := findLogLine(readLogLines(t, filename), "any with")
+	p, _ := entry["people"].(map[string]interface{})
+	if p["full_name"] != "张三" || p["age"] != float64(11) {
+		t.Errorf("expected custom encoded people, got %v", entry["people"])
+	}
+	if entry["badError"] == nil {
+		t.Errorf("expected encode error field, got %v", entry)
+	}
+}
+
+func TestWithLargeAnyWarning(t *testing.T) {
+	big := make([]int, 100)
+	filename := initTestLogger(t, WithLargeAnyWarning(64))
+	t.Cleanup(func() {
+		onceKeys.Range(func(key, _ interface{}) bool {
+			if strings.HasPrefix(key.(string), "large_any:") {
+				onceKeys.Delete(key)
+			}
+			return true
+		})
+	})
+	for i := 0; i < 3; i++ {
+		Info("large any", Any("data", big), Any("small", []int{1}))
+	}
+
+	var warnings []map[string]interface{}
+	for _, line := range readLogLines(t, filename) {
+		if line["msg"] == "large value logged with Any, consider logging only the needed fields" {
+			warnings = append(warnings, line)
+		}
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("expected 1 warning, got %d", len(warnings))
+	}
+	if warnings[0]["any_key"] != "data" || warnings[0]["level"] != "warn" {
+		t.Errorf("unexpected warning %v", warnings[0])
+	}
+	if caller, _ := warnings[0]["caller"].(string); !strings.Contains(caller, "any_test.go") {
+		t.Errorf("expected caller in any_test.go, got %v", warnings[0]["caller"])
+	}
+
+	// 未设置时不检查
+	filename = initTestLogger(t)
+	Info("large any", Any("data", big))
+	if entry := findLogLine(readLogLines(t, filename), "large value logged with Any, consider logging only the needed fields"); entry != nil {
+		t.Errorf("expected no warning by default, got %v", entry)
+	}
+}
+
+func TestSortedAny(t *testing.T) {
+	body := map[string]interface{}{
+		"zeta":  1,
+		"alpha": "a",
+		"mid":   map[string]interface{}{"y": true, "b": nil, "a": []int{1, 2}},
+		"beta":  2.5,
+	}
+	if m := FieldsToMap(SortedAny("n", 1)); m["n"] != int64(1) {
+		t.Fatalf("expected non-map value to fall back to Any, got %v", m["n"])
+	}
+
+	want := `"body":{"alpha":"a","beta":2.5,"mid":{"a":[1,2],"b":null,"y":true},"zeta":1},"ids":{"10":"x","2":"y"}`
+	for i := 0; i < 5; i++ {
+		enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+		out, err := enc.EncodeEntry(zapcore.Entry{Message: "sorted"}, []Field{SortedAny("body", body), SortedAny("ids", map[int]string{10: "x", 2: "y"})})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out.String(), want) {
+			t.Fatalf("expected sorted map output, got %s", out.String())
+		}
+	}
+}
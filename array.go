@@ -0,0 +1,80 @@
+package logger
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 整数数组最多输出的元素个数，0表示不限制
+var compactIntsLimit atomic.Int64
+
+// Int64s []int64类型，例如批量的id，输出为json数组
+func Int64s(key string, vals []int64) Field {
+	return zap.Array(key, int64s(vals))
+}
+
+// Uint64s []uint64类型，输出为json数组
+func Uint64s(key string, vals []uint64) Field {
+	return zap.Array(key, uint64s(vals))
+}
+
+// Int32s []int32类型，输出为json数组
+func Int32s(key string, vals []int32) Field {
+	return zap.Array(key, int32s(vals))
+}
+
+// Strings []string类型，输出为json数组
+func Strings(key string, vals []string) Field {
+	return zap.Strings(key, vals)
+}
+
+type int64s []int64
+
+func (nums int64s) MarshalLogArray(arr zapcore.ArrayEncoder) error {
+	n := compactLen(len(nums))
+	for _, v := range nums[:n] {
+		arr.AppendInt64(v)
+	}
+	appendMore(arr, len(nums)-n)
+	return nil
+}
+
+type uint64s []uint64
+
+func (nums uint64s) MarshalLogArray(arr zapcore.ArrayEncoder) error {
+	n := compactLen(len(nums))
+	for _, v := range nums[:n] {
+		arr.AppendUint64(v)
+	}
+	appendMore(arr, len(nums)-n)
+	return nil
+}
+
+type int32s []int32
+
+func (nums int32s) MarshalLogArray(arr zapcore.ArrayEncoder) error {
+	n := compactLen(len(nums))
+	for _, v := range nums[:n] {
+		arr.AppendInt32(v)
+	}
+	appendMore(arr, len(nums)-n)
+	return nil
+}
+
+// 根据限制返回输出的元素个数
+func compactLen(n int) int {
+	if limit := int(compactIntsLimit.Load()); limit > 0 && n > limit {
+		return limit
+	}
+	return n
+}
+
+// 省略元素时在数组末尾添加"...(N more)"
+func appendMore(arr zapcore.ArrayEncoder, more int) {
+	if more > 0 {
+		arr.AppendString(fmt.Sprintf("...(%d more)", more))
+	}
+}
@@ -0,0 +1,24 @@
+package logger
+
+import "testing"
+
+func TestCompactInts(t *testing.T) {
+	filename := initTestLogger(t, WithCompactInts(3))
+	ids := make([]int64, 10000)
+	for i := range ids {
+		ids[i] = int64(i)
+	}
+	Info("batch ids", Int64s("ids", ids), Int32s("small", []int32{1, 2}))
+
+	entry := findLogLine(readLogLines(t, filename), "batch ids")
+	got, ok := entry["ids"].([]interface{})
+	if !ok || len(got) != 4 {
+		t.Fatalf("expected 3 ids and a marker, got %v", entry["ids"])
+	}
+	if got[0] != float64(0) || got[2] != float64(2) || got[3] != "...(9997 more)" {
+		t.Errorf("unexpected truncated ids %v", got)
+	}
+	if small, _ := entry["small"].([]interface{}); len(small) != 2 {
+		t.Errorf("expected short slice to be complete, got %v", entry["small"])
+	}
+}
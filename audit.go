@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 审计日志logger，没有设置审计日志文件时为nil
+var auditLogger atomic.Pointer[zap.Logger]
+
+// Audit 输出审计日志到WithAudit设置的文件，固定输出timestamp、actor、action、resource、outcome字段和fields，
+// 不受日志级别影响，总是输出，没有设置审计日志文件时不输出
+//
+//	eg: Audit("user-1", "delete", "order/123", "success", String("ip", ip))
+func Audit(actor, action, resource, outcome string, fields ...Field) {
+	logger := auditLogger.Load()
+	if logger == nil {
+		return
+	}
+
+	logger.Info("", append([]Field{
+		String("actor", actor),
+		String("action", action),
+		String("resource", resource),
+		String("outcome", outcome),
+	}, fields...)...)
+}
+
+// 构建审计日志logger，只输出时间和字段，追加写入文件
+func (o *options) buildAuditLogger() (*zap.Logger, error) {
+	if o.auditFilename == "" {
+		return nil, nil
+	}
+
+	sink, err := o.open(o.auditFilename)
+	if err != nil {
+		return nil, err
+	}
+
+	encoderConfig := zapcore.EncoderConfig{
+		TimeKey:        "timestamp",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.SecondsDurationEncoder,
+	}
+	if o.utc {
+		encoderConfig.EncodeTime = utcTimeEncoder(encoderConfig.EncodeTime)
+	}
+
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(encoderConfig), sink, zap.LevelEnablerFunc(func(zapcore.Level) bool { return true }))
+	return zap.New(core, zap.WithClock(o.getClock())), nil
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+func TestAudit(t *testing.T) {
+	auditFilename := filepath.Join(t.TempDir(), "audit.log")
+	filename := initTestLogger(t, WithLogLevel("error"), WithAudit(auditFilename))
+	Audit("user-1", "delete", "order/123", "success", String("ip", "10.0.0.1"))
+
+	lines := readLogLines(t, auditFilename)
+	if len(lines) != 1 {
+		t.Fatalf("expected 1 audit entry, got %d", len(lines))
+	}
+	entry := lines[0]
+	keys := make([]string, 0, len(entry))
+	for k := range entry {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	want := []string{"action", "actor", "ip", "outcome", "resource", "timestamp"}
+	if len(keys) != len(want) {
+		t.Fatalf("expected keys %v, got %v", want, keys)
+	}
+	for i := range want {
+		if keys[i] != want[i] {
+			t.Fatalf("expected keys %v, got %v", want, keys)
+		}
+	}
+	if entry["actor"] != "user-1" || entry["action"] != "delete" || entry["resource"] != "order/123" || entry["outcome"] != "success" {
+		t.Errorf("unexpected audit entry %v", entry)
+	}
+
+	for _, line := range readLogLines(t, filename) {
+		if line["actor"] != nil {
+			t.Errorf("unexpected audit entry in app log %v", line)
+		}
+	}
+}
+
+func TestAuditClosedOnReinit(t *testing.T) {
+	auditFilename := filepath.Join(t.TempDir(), "audit.log")
+	initTestLogger(t, WithAudit(auditFilename))
+	Audit("user-1", "login", "session", "success")
+
+	initTestLogger(t)
+	if isFileOpen(auditFilename) {
+		t.Errorf("expected %s to be closed after re-init", auditFilename)
+	}
+	Audit("user-1", "logout", "session", "success") // 没有设置审计日志文件，不输出
+	if lines := readLogLines(t, auditFilename); len(lines) != 1 {
+		t.Errorf("expected 1 audit entry, got %d", len(lines))
+	}
+
+	initTestLogger(t, WithAudit(auditFilename))
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	if isFileOpen(auditFilename) || auditLogger.Load() != nil {
+		t.Error("expected audit logger to be closed with the logger")
+	}
+}
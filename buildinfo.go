@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"runtime/debug"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// SetBuildInfo设置的版本信息字段，写入日志时添加
+var buildInfo atomic.Pointer[[]Field]
+
+// SetBuildInfo 设置每条日志都携带的版本字段version和git_commit，一般为通过-ldflags注入的值，
+// 在初始化之前或之后调用都生效，重复调用时替换原来的值
+//
+//	eg: SetBuildInfo(version, gitCommit)
+func SetBuildInfo(version, commit string) {
+	fields := []Field{String("version", version), String("git_commit", commit)}
+	buildInfo.Store(&fields)
+}
+
+// 写入时添加SetBuildInfo设置的字段的core，没有设置时直接使用内部的core
+type buildInfoCore struct {
+	zapcore.Core
+}
+
+func (c *buildInfoCore) With(fields []Field) zapcore.Core {
+	return &buildInfoCore{Core: c.Core.With(fields)}
+}
+
+func (c *buildInfoCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if buildInfo.Load() == nil {
+		return c.Core.Check(ent, ce)
+	}
+	return (&interceptCore{Core: c.Core, fn: appendBuildInfo}).Check(ent, ce)
+}
+
+func (c *buildInfoCore) Write(ent zapcore.Entry, fields []Field) error {
+	_, fields, _ = appendBuildInfo(ent, fields)
+	return c.Core.Write(ent, fields)
+}
+
+func appendBuildInfo(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+	if info := buildInfo.Load(); info != nil {
+		fields = append(fields[:len(fields):len(fields)], *info...)
+	}
+	return ent, fields, true
+}
+
+// LogBuildInfo 输出一条info级别的程序版本信息日志，字段为version、git_commit、build_date和go_version，
+// 参数为空时从runtime/debug.ReadBuildInfo读取(模块版本、vcs.revision、vcs.time)，一般在程序启动时调用
+//
+//	eg: LogBuildInfo(version, commit, date) // 通过-ldflags "-X main.version=..."设置
+func LogBuildInfo(version, commit, date string) {
+	getLogger().Info("build info", buildInfoFields(version, commit, date)...)
+}
+
+// StampBuildInfo 把程序版本信息version、git_commit、build_date、go_version作为全局字段，之后输出的日志都携带这些字段，
+// 参数为空时的处理与LogBuildInfo相同
+func StampBuildInfo(version, commit, date string) {
+	AddGlobalFields(buildInfoFields(version, commit, date)...)
+}
+
+func buildInfoFields(version, commit, date string) []Field {
+	var goVersion string
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		goVersion = bi.GoVersion
+		if version == "" {
+			version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch {
+			case s.Key == "vcs.revision" && commit == "":
+				commit = s.Value
+			case s.Key == "vcs.time" && date == "":
+				date = s.Value
+			}
+		}
+	}
+
+	return []Field{
+		String("version", version),
+		String("git_commit", commit), // 与SetBuildInfo相同的字段名
+		String("build_date", date),
+		String("go_version", goVersion),
+	}
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLogBuildInfo(t *testing.T) {
+	filename := initTestLogger(t)
+	LogBuildInfo("v1.2.3", "abc123", "2024-06-01")
+	Info("before stamp")
+	StampBuildInfo("v1.2.3", "", "")
+	Info("after stamp")
+
+	lines := readLogLines(t, filename)
+	entry := findLogLine(lines, "build info")
+	if entry["version"] != "v1.2.3" || entry["git_commit"] != "abc123" || entry["build_date"] != "2024-06-01" || entry["go_version"] != runtime.Version() {
+		t.Errorf("unexpected build info %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "buildinfo_test.go") {
+		t.Errorf("expected caller in buildinfo_test.go, got %v", entry["caller"])
+	}
+	if entry := findLogLine(lines, "before stamp"); entry["version"] != nil {
+		t.Errorf("unexpected version before stamp %v", entry)
+	}
+	if entry := findLogLine(lines, "after stamp"); entry["version"] != "v1.2.3" || entry["go_version"] != runtime.Version() {
+		t.Errorf("expected build info fields after stamp, got %v", entry)
+	}
+}
+
+func TestSetBuildInfo(t *testing.T) {
+	t.Cleanup(func() { buildInfo.Store(nil) })
+
+	// 初始化之前设置
+	SetBuildInfo("v1.0.0", "abc123")
+	filename := initTestLogger(t)
+	Info("built with info")
+	entry := findLogLine(readLogLines(t, filename), "built with info")
+	if entry["version"] != "v1.0.0" || entry["git_commit"] != "abc123" {
+		t.Errorf("expected build info fields, got %v", entry)
+	}
+
+	// 初始化之后设置，重复设置时替换原来的值
+	buildInfo.Store(nil)
+	filename = initTestLogger(t)
+	logger := WithFields(String("svc", "api"))
+	SetBuildInfo("v1.0.1", "def456")
+	SetBuildInfo("v1.0.2", "fed654")
+	Info("after set")
+	logger.Info("derived after set")
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.Contains(line, "after set") && (strings.Count(line, `"version"`) != 1 || strings.Count(line, `"git_commit"`) != 1) {
+			t.Errorf("expected a single version and git_commit field, got %s", line)
+		}
+	}
+	lines := readLogLines(t, filename)
+	for _, msg := range []string{"after set", "derived after set"} {
+		if entry := findLogLine(lines, msg); entry["version"] != "v1.0.2" || entry["git_commit"] != "fed654" {
+			t.Errorf("expected latest build info fields, got %v", entry)
+		}
+	}
+}
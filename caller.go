@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 把调用位置拆分为caller_file和caller_line两个字段
+func newCallerFieldsCore(core zapcore.Core, fullCaller bool, trimPrefix string, moduleName string) zapcore.Core {
+	c := &callerFields{fullCaller: fullCaller, trimPrefix: trimPrefix, moduleName: moduleName}
+	return &interceptCore{Core: core, fn: c.intercept}
+}
+
+type callerFields struct {
+	fullCaller bool
+	trimPrefix string
+	moduleName string
+}
+
+func (c *callerFields) intercept(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+	if ent.Caller.Defined {
+		fields = append(fields[:len(fields):len(fields)], String("caller_file", c.callerFile(ent.Caller)), Int("caller_line", ent.Caller.Line))
+		ent.Caller.Defined = false
+	}
+	return ent, fields, true
+}
+
+func (c *callerFields) callerFile(caller zapcore.EntryCaller) string {
+	if c.moduleName != "" {
+		return moduleCallerFile(caller, c.moduleName)
+	}
+	if c.fullCaller {
+		if c.trimPrefix != "" {
+			return strings.TrimPrefix(caller.File, strings.TrimSuffix(c.trimPrefix, "/")+"/")
+		}
+		return caller.File
+	}
+
+	return shortCallerFile(caller.File)
+}
+
+// 和默认的调用位置一样只保留最后一级目录和文件名
+func shortCallerFile(file string) string {
+	idx := strings.LastIndexByte(file, '/')
+	if idx == -1 {
+		return file
+	}
+	idx = strings.LastIndexByte(file[:idx], '/')
+	if idx == -1 {
+		return file
+	}
+	return file[idx+1:]
+}
+
+// 调用位置显示为相对模块根目录的路径，例如："internal/svc/file.go:123"
+func moduleCallerEncoder(module string) zapcore.CallerEncoder {
+	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		if !caller.Defined {
+			enc.AppendString("undefined")
+			return
+		}
+		enc.AppendString(moduleCallerFile(caller, module) + ":" + strconv.Itoa(caller.Line))
+	}
+}
+
+// 根据函数所在的包路径得到相对模块根目录的文件路径，与编译时的目录无关，
+// 函数不属于模块时在文件路径中查找模块路径，都找不到时只保留最后一级目录和文件名
+func moduleCallerFile(caller zapcore.EntryCaller, module string) string {
+	module = strings.TrimSuffix(module, "/")
+	file := caller.File[strings.LastIndexByte(caller.File, '/')+1:]
+
+	pkg := caller.Function
+	if idx := strings.LastIndexByte(pkg, '/'); idx != -1 {
+		if dot := strings.IndexByte(pkg[idx:], '.'); dot != -1 {
+			pkg = pkg[:idx+dot]
+		}
+	} else if dot := strings.IndexByte(pkg, '.'); dot != -1 {
+		pkg = pkg[:dot]
+	}
+	if pkg == module {
+		return file
+	}
+	if strings.HasPrefix(pkg, module+"/") {
+		return pkg[len(module)+1:] + "/" + file
+	}
+
+	if idx := strings.Index(caller.File, "/"+module+"/"); idx != -1 {
+		return caller.File[idx+len(module)+2:]
+	}
+	return shortCallerFile(caller.File)
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithCallerFields(t *testing.T) {
+	filename := initTestLogger(t, WithCallerFields())
+	_, _, line, _ := runtime.Caller(0)
+	Info("caller fields")
+
+	entry := findLogLine(readLogLines(t, filename), "caller fields")
+	if _, ok := entry["caller"]; ok {
+		t.Errorf("unexpected caller field: %v", entry["caller"])
+	}
+	if file, ok := entry["caller_file"].(string); !ok || file == "" {
+		t.Errorf("expected caller_file, got %v", entry["caller_file"])
+	}
+	if got, ok := entry["caller_line"].(float64); !ok || int(got) != line+1 {
+		t.Errorf("expected integer caller_line %d, got %v", line+1, entry["caller_line"])
+	}
+}
+
+func TestWithModuleName(t *testing.T) {
+	filename := initTestLogger(t, WithModuleName("github.com/zhufuyi"))
+	_, _, line, _ := runtime.Caller(0)
+	Info("module caller")
+
+	entry := findLogLine(readLogLines(t, filename), "module caller")
+	if want := fmt.Sprintf("logger/caller_test.go:%d", line+1); entry["caller"] != want {
+		t.Errorf("expected caller %s, got %v", want, entry["caller"])
+	}
+
+	tests := []struct {
+		caller zapcore.EntryCaller
+		want   string
+	}{
+		{zapcore.EntryCaller{Function: "github.com/foo/bar/internal/svc.(*Server).Run", File: "/home/runner/work/bar/internal/svc/server.go"}, "internal/svc/server.go"},
+		{zapcore.EntryCaller{Function: "github.com/foo/bar.main.func1", File: "/build/main.go"}, "main.go"},
+		{zapcore.EntryCaller{Function: "", File: "/root/go/src/github.com/foo/bar/pkg/util/util.go"}, "pkg/util/util.go"},
+		{zapcore.EntryCaller{Function: "main.main", File: "/build/cmd/app/main.go"}, "app/main.go"},
+	}
+	for _, tt := range tests {
+		if got := moduleCallerFile(tt.caller, "github.com/foo/bar"); got != tt.want {
+			t.Errorf("moduleCallerFile(%s) = %s, want %s", tt.caller.File, got, tt.want)
+		}
+	}
+}
+
+func TestCallerFieldsCopiesFields(t *testing.T) {
+	c := &callerFields{}
+	fields := make([]Field, 1, 3)
+	fields[0] = String("k", "v")
+	spare := fields[:3]
+
+	ent := zapcore.Entry{Caller: zapcore.NewEntryCaller(0, "/src/main.go", 42, true)}
+	_, got, _ := c.intercept(ent, fields)
+	if len(got) != 3 || spare[1].Key != "" || spare[2].Key != "" {
+		t.Errorf("expected caller fields appended to a copy, got %v, caller slice %v", got, spare)
+	}
+}
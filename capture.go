@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// Capture 执行fn期间把全局logger替换为内存中的observer，返回fn输出的所有级别的日志，执行完后恢复原来的logger，
+// 替换对所有goroutine生效，只适合在顺序执行的测试中使用
+//
+//	eg: entries := Capture(func() { doWork() })
+func Capture(fn func()) []observer.LoggedEntry {
+	core, logs := observer.New(zapcore.DebugLevel)
+
+	loggerMu.Lock()
+	old, oldConfig, oldClose, oldState := defaultLogger, effectiveConfig, closeLogger, loadState()
+	defaultLogger, closeLogger = zap.New(core, zap.AddCaller()), nil // 恢复时不关闭原来logger的文件
+	loggerMu.Unlock()
+	defer setLogger(old, oldConfig, oldClose, oldState)
+
+	fn()
+	return logs.All()
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestCapture(t *testing.T) {
+	filename := initTestLogger(t)
+	entries := Capture(func() {
+		Debug("step one", Int("n", 1))
+		WithFields(String("component", "worker")).Warn("step two")
+	})
+
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 captured entries, got %d", len(entries))
+	}
+	if entries[0].Message != "step one" || entries[0].Level != zapcore.DebugLevel || entries[0].ContextMap()["n"] != int64(1) {
+		t.Errorf("unexpected first entry %+v", entries[0])
+	}
+	if entries[1].Message != "step two" || entries[1].ContextMap()["component"] != "worker" {
+		t.Errorf("unexpected second entry %+v", entries[1])
+	}
+
+	// 恢复原来的logger
+	Info("after capture")
+	lines := readLogLines(t, filename)
+	if findLogLine(lines, "after capture") == nil {
+		t.Error("expected global logger to be restored")
+	}
+	if findLogLine(lines, "step one") != nil {
+		t.Error("expected captured entries not to reach the original output")
+	}
+}
@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"context"
+	"time"
+)
+
+type checkpointKey string
+
+// Checkpoint 在context中记录名称为name的时间点，返回新的context
+func Checkpoint(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, checkpointKey(name), time.Now())
+}
+
+// SinceCheckpoint 返回从名称为name的时间点到现在的耗时字段，字段名为"since_"+name，
+// context中没有该时间点时耗时为-1ns
+func SinceCheckpoint(ctx context.Context, name string) Field {
+	key := "since_" + name
+	if ctx != nil {
+		if t, ok := ctx.Value(checkpointKey(name)).(time.Time); ok {
+			return Duration(key, time.Since(t))
+		}
+	}
+	return Duration(key, -1)
+}
+
+// TimeOp 记录当前时间，返回的函数被调用时输出info级别日志，携带操作名称name和耗时duration字段，
+//
+//	eg: defer TimeOp("db.query")()
+func TimeOp(name string) func(fields ...Field) {
+	start := time.Now()
+	return func(fields ...Field) {
+		getLogger().Info("operation finished", append([]Field{String("name", name), Duration("duration", time.Since(start))}, fields...)...)
+	}
+}
@@ -0,0 +1,41 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSinceCheckpoint(t *testing.T) {
+	ctx := Checkpoint(context.Background(), "parse")
+	time.Sleep(10 * time.Millisecond)
+
+	field := SinceCheckpoint(ctx, "parse")
+	if field.Key != "since_parse" || time.Duration(field.Integer) < 10*time.Millisecond {
+		t.Errorf("unexpected field %+v", field)
+	}
+
+	if field := SinceCheckpoint(context.Background(), "missing"); time.Duration(field.Integer) != -1 {
+		t.Errorf("expected -1 for missing checkpoint, got %d", field.Integer)
+	}
+}
+
+func TestTimeOp(t *testing.T) {
+	filename := initTestLogger(t)
+
+	done := TimeOp("db.query")
+	time.Sleep(50 * time.Millisecond)
+	done(String("table", "user"))
+
+	entry := findLogLine(readLogLines(t, filename), "operation finished")
+	if entry["name"] != "db.query" || entry["table"] != "user" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if d, _ := entry["duration"].(float64); d < 0.05 || d > 0.5 {
+		t.Errorf("expected duration about 0.05s, got %v", entry["duration"])
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "checkpoint_test.go") {
+		t.Errorf("expected caller in checkpoint_test.go, got %v", entry["caller"])
+	}
+}
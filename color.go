@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/term"
+)
+
+// 默认的日志级别颜色，与zap的CapitalColorLevelEncoder一致
+var defaultLevelColors = map[zapcore.Level]string{
+	zapcore.DebugLevel:  "35", // 紫色
+	zapcore.InfoLevel:   "34", // 蓝色
+	zapcore.WarnLevel:   "33", // 黄色
+	zapcore.ErrorLevel:  "31", // 红色
+	zapcore.DPanicLevel: "31",
+	zapcore.PanicLevel:  "31",
+	zapcore.FatalLevel:  "31",
+}
+
+var ansiCodeRegexp = regexp.MustCompile(`^[0-9]+(;[0-9]+)*$`)
+
+// 给日志级别添加颜色，colors中无效或空的颜色使用默认颜色
+func colorLevelEncoder(base zapcore.LevelEncoder, colors map[zapcore.Level]string) zapcore.LevelEncoder {
+	codes := make(map[zapcore.Level]string, len(defaultLevelColors))
+	for level, code := range defaultLevelColors {
+		codes[level] = code
+	}
+	for level, code := range colors {
+		if ansiCodeRegexp.MatchString(code) {
+			codes[level] = code
+		}
+	}
+
+	return func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		arr := &primitiveCapture{}
+		base(level, arr)
+		if len(arr.values) == 0 {
+			return
+		}
+
+		code, ok := codes[level]
+		if !ok {
+			enc.AppendString(toString(arr.values[0]))
+			return
+		}
+		enc.AppendString(fmt.Sprintf("\x1b[%sm%s\x1b[0m", code, toString(arr.values[0])))
+	}
+}
+
+// 根据color的返回值决定是否给日志级别添加颜色，每个输出分别判断
+func switchColorLevelEncoder(base zapcore.LevelEncoder, colors map[zapcore.Level]string, color func() bool) zapcore.LevelEncoder {
+	colored := colorLevelEncoder(base, colors)
+	return func(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+		if color != nil && color() {
+			colored(level, enc)
+			return
+		}
+		base(level, enc)
+	}
+}
+
+func constColor(enable bool) func() bool {
+	return func() bool { return enable }
+}
+
+// 输出w是否显示颜色，force为WithColor设置的值，未设置时只有w为终端时才显示颜色
+func colorOutput(force *bool, w interface{}) bool {
+	if force != nil {
+		return *force
+	}
+	f, ok := w.(*os.File)
+	return ok && isTerminal(f)
+}
+
+// 判断文件是否为终端
+func isTerminal(f *os.File) bool {
+	return term.IsTerminal(int(f.Fd()))
+}
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestColorLevelEncoder(t *testing.T) {
+	encode := colorLevelEncoder(zapcore.LowercaseLevelEncoder, map[zapcore.Level]string{
+		zapcore.InfoLevel: "36",
+		zapcore.WarnLevel: "invalid",
+	})
+
+	tests := []struct {
+		level zapcore.Level
+		want  string
+	}{
+		{zapcore.InfoLevel, "\x1b[36minfo\x1b[0m"},
+		{zapcore.WarnLevel, "\x1b[33mwarn\x1b[0m"},
+		{zapcore.ErrorLevel, "\x1b[31merror\x1b[0m"},
+	}
+	for _, tt := range tests {
+		arr := &primitiveCapture{}
+		encode(tt.level, arr)
+		if len(arr.values) != 1 || arr.values[0] != tt.want {
+			t.Errorf("level %s: expected %q, got %v", tt.level, tt.want, arr.values)
+		}
+	}
+}
+
+func TestWithColor(t *testing.T) {
+	defer resetLogger(t)
+
+	// 测试时stdout不是终端，默认不显示颜色
+	buf := &bytes.Buffer{}
+	if err := Init(WithTee(buf)); err != nil {
+		t.Fatal(err)
+	}
+	Info("no color")
+	if strings.Contains(buf.String(), "\x1b[") {
+		t.Errorf("unexpected ANSI codes in non-TTY output %q", buf.String())
+	}
+
+	// WithColor(true)强制显示颜色
+	buf.Reset()
+	if err := Init(WithColor(true), WithTee(buf)); err != nil {
+		t.Fatal(err)
+	}
+	Info("with color")
+	if !strings.Contains(buf.String(), "\x1b[34minfo\x1b[0m") {
+		t.Errorf("expected ANSI codes in output %q", buf.String())
+	}
+}
+
+func TestColorPerSink(t *testing.T) {
+	// 未设置WithColor时只有终端才显示颜色，每个输出分别判断
+	if colorOutput(nil, &bytes.Buffer{}) {
+		t.Error("expected no color for a buffer")
+	}
+	f, err := os.CreateTemp(t.TempDir(), "color")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if colorOutput(nil, f) {
+		t.Error("expected no color for a regular file")
+	}
+	force := true
+	if !colorOutput(&force, &bytes.Buffer{}) {
+		t.Error("expected WithColor(true) to apply to every output")
+	}
+
+	// SetOutput替换输出后重新判断是否显示颜色
+	out := newSwapWriteSyncer(zapcore.AddSync(io.Discard))
+	out.setColor(nil, os.Stdout)
+	out.color.Store(true) // 模拟stdout为终端
+	out.swap(zapcore.AddSync(&bytes.Buffer{}), &bytes.Buffer{})
+	if out.colored() {
+		t.Error("expected no color after redirecting to a buffer")
+	}
+
+	encode := switchColorLevelEncoder(zapcore.LowercaseLevelEncoder, nil, out.colored)
+	arr := &primitiveCapture{}
+	encode(zapcore.InfoLevel, arr)
+	if len(arr.values) != 1 || arr.values[0] != "info" {
+		t.Errorf("expected plain level, got %v", arr.values)
+	}
+	out.color.Store(true)
+	arr = &primitiveCapture{}
+	encode(zapcore.InfoLevel, arr)
+	if len(arr.values) != 1 || arr.values[0] != "\x1b[34minfo\x1b[0m" {
+		t.Errorf("expected colored level, got %v", arr.values)
+	}
+}
@@ -0,0 +1,330 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Config 日志生效的配置
+type Config struct {
+	Level    string // 日志级别，debug, info, warn, error
+	Encoding string // 输出格式，json或console
+	IsSave   bool   // 是否输出到文件
+	Filename string // 日志文件路径，IsSave为true时有效
+	Stderr   bool   // 输出到控台时warn及以上级别的日志是否输出到stderr，为false(包括零值)时Options会添加WithSingleStream
+
+	FailoverFilenames []string // Filename写入失败时按顺序切换的备用文件
+
+	RotationMode     string        // 日志文件的切割方式，size或time，为空表示不切割
+	RotationInterval time.Duration // 按时间切割的周期
+	RotationPattern  string        // 按时间切割时文件名中日期的格式
+	RotationMaxSize  int           // 按大小切割时单个文件的最大大小，单位MB
+
+	FullCaller       bool   // 是否显示完整的调用路径
+	CallerTrimPrefix string // 调用路径去掉的前缀
+	CallerFields     bool   // 调用位置是否拆分为caller_file和caller_line
+	ModuleName       string // 调用位置相对的模块路径
+
+	ShortLevels   bool                   // 控台是否使用单个字母表示日志级别
+	UTC           bool                   // 日志时间是否使用UTC时区
+	Severity      bool                   // 日志级别是否输出为数字级别severity
+	KeepLevel     bool                   // Severity为true时是否同时保留文字日志级别
+	Color         bool                   // 控台日志级别是否显示颜色
+	FieldOrder    []string               // 基础字段的输出顺序
+	SortedFields  bool                   // 控台字段是否按字段名排序
+	InitialFields map[string]interface{} // 每条日志都携带的字段
+	MessagePrefix string                 // 日志消息的前缀
+	CompactInts   int                    // 整数数组最多输出的元素个数，0表示不限制
+	SchemaVersion string                 // 日志格式的版本字段schema_version，为空表示不携带
+	ProcessInfo   bool                   // 是否携带pid和hostname字段
+	EpochField    string                 // 毫秒时间戳字段名，为空表示不输出
+
+	StacktraceTrim         bool     // 是否去掉堆栈中zap和本包的帧
+	StacktraceTrimPrefixes []string // 堆栈中另外去掉的函数名前缀
+
+	LargeAnyLimit int // Any的值超过该字节数时输出警告，0表示不检查
+
+	SamplingTick       time.Duration // 采样周期，为0表示不采样
+	SamplingFirst      int           // 每个采样周期内输出的前几条日志
+	SamplingThereafter int           // 之后每几条输出一条
+	SamplingKey        string        // 按context中该key的值分别采样，为空表示不区分
+	RateLimit          int           // 每秒最多输出的日志条数，0表示不限制
+
+	TeeCount         int           // 同时输出的其他输出数量
+	EventLogSource   string        // Windows事件日志来源名称
+	CoreCount        int           // WithCore添加的输出数量
+	Journald         bool          // 是否同时写入systemd journal
+	WriteTimeout     time.Duration // 每次写入输出的超时时间，0表示不限制
+	Fallback         bool          // 输出关闭后是否切换到FallbackFilename
+	FallbackFilename string        // 输出关闭后的备用文件，为空表示丢弃
+	AuditFilename    string        // 审计日志文件，为空表示不输出审计日志
+}
+
+// 已生效的配置，由loggerMu保护
+var effectiveConfig Config
+
+// EffectiveConfig 返回当前生效配置的副本，未初始化时以默认配置初始化，
+// 可以在启动时输出或者通过debug接口查看
+func EffectiveConfig() Config {
+	loadLogger()
+
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+
+	cfg := effectiveConfig
+	cfg.FieldOrder = append([]string(nil), effectiveConfig.FieldOrder...)
+	cfg.FailoverFilenames = append([]string(nil), effectiveConfig.FailoverFilenames...)
+	if effectiveConfig.InitialFields != nil {
+		cfg.InitialFields = make(map[string]interface{}, len(effectiveConfig.InitialFields))
+		for k, v := range effectiveConfig.InitialFields {
+			cfg.InitialFields[k] = v
+		}
+	}
+	return cfg
+}
+
+// PrintConfig 以一条info级别日志输出当前生效的配置，包括日志级别(log_level)、格式、输出、调用位置、堆栈和切割配置，
+// 不受设置的日志级别影响，总是输出，用于排查部署问题
+func PrintConfig() {
+	cfg := EffectiveConfig()
+
+	outputs := []string{"stdout"}
+	if cfg.Stderr {
+		outputs = append(outputs, "stderr")
+	}
+	if cfg.IsSave {
+		outputs = append([]string{cfg.Filename}, cfg.FailoverFilenames...)
+	}
+
+	fields := []Field{
+		String("log_level", cfg.Level),
+		String("encoding", cfg.Encoding),
+		Strings("outputs", outputs),
+		Int("tee_count", cfg.TeeCount),
+		Int("core_count", cfg.CoreCount),
+		Bool("full_caller", cfg.FullCaller),
+		String("caller_trim_prefix", cfg.CallerTrimPrefix),
+		Bool("caller_fields", cfg.CallerFields),
+		String("module_name", cfg.ModuleName),
+		String("stacktrace_level", zapcore.ErrorLevel.String()),
+		String("rotation_mode", cfg.RotationMode),
+	}
+	if cfg.RotationMode == RotationTime {
+		fields = append(fields, Duration("rotation_interval", cfg.RotationInterval), String("rotation_pattern", cfg.RotationPattern))
+	}
+	if cfg.RotationMode == RotationSize {
+		fields = append(fields, Int("rotation_max_size_mb", cfg.RotationMaxSize))
+	}
+	if cfg.SamplingTick > 0 {
+		fields = append(fields, Duration("sampling_tick", cfg.SamplingTick), Int("sampling_first", cfg.SamplingFirst), Int("sampling_thereafter", cfg.SamplingThereafter))
+	}
+	fields = append(fields,
+		StringNonEmpty("fallback_filename", cfg.FallbackFilename),
+		StringNonEmpty("audit_filename", cfg.AuditFilename),
+		StringNonEmpty("event_log_source", cfg.EventLogSource),
+		Bool("journald", cfg.Journald),
+	)
+
+	// 替换日志级别判断，日志级别为warn等高于info的级别时也输出
+	getLogger().WithOptions(replaceLevel(func(zapcore.LevelEnabler) zapcore.LevelEnabler {
+		return zapcore.DebugLevel
+	})).Info("logger config", fields...)
+}
+
+// CurrentConfig 返回当前生效配置的副本，同EffectiveConfig，修改后通过Options转换为选项重新初始化，
+// 用于派生只有部分配置不同的logger，例如只修改日志文件
+//
+//	eg:
+//	cfg := CurrentConfig()
+//	cfg.Filename = "sub.log"
+//	err := Init(cfg.Options()...)
+func CurrentConfig() Config {
+	return EffectiveConfig()
+}
+
+// Options 把配置转换为初始化选项，WithTee、WithFailover、WithCore、WithClock、回调函数等不能用配置表示的选项需要另外添加，
+// 初始化之后通过AddGlobalFields、AddFilter、SetBuildInfo等函数添加的设置也不包含在配置中，
+// 注意输出到控台时Stderr为false会把所有日志输出到stdout，零值Config{}与不带选项的Init不同，需要拆分输出时设置Stderr为true
+func (c Config) Options() []Option {
+	opts := []Option{WithLogLevel(c.Level), WithEncoding(c.Encoding)}
+	if c.IsSave && len(c.FailoverFilenames) > 0 {
+		opts = append(opts, WithSaveFailover(append([]string{c.Filename}, c.FailoverFilenames...)...))
+	} else if c.IsSave {
+		opts = append(opts, WithSave(c.Filename))
+	} else if !c.Stderr {
+		opts = append(opts, WithSingleStream())
+	}
+	if c.RotationMode != "" {
+		opts = append(opts, WithRotationMode(c.RotationMode))
+	}
+	if c.RotationInterval != 0 {
+		opts = append(opts, WithRotationInterval(c.RotationInterval))
+	}
+	if c.RotationPattern != "" {
+		opts = append(opts, WithRotationPattern(c.RotationPattern))
+	}
+	if c.RotationMaxSize > 0 {
+		opts = append(opts, WithMaxSize(c.RotationMaxSize))
+	}
+
+	if c.CallerTrimPrefix != "" {
+		opts = append(opts, WithCallerTrimPrefix(c.CallerTrimPrefix))
+	} else if c.FullCaller {
+		opts = append(opts, WithFullCaller())
+	}
+	if c.CallerFields {
+		opts = append(opts, WithCallerFields())
+	}
+	if c.ModuleName != "" {
+		opts = append(opts, WithModuleName(c.ModuleName))
+	}
+
+	if c.ShortLevels {
+		opts = append(opts, WithShortLevels())
+	}
+	if c.UTC {
+		opts = append(opts, WithUTC())
+	}
+	if c.Severity {
+		opts = append(opts, WithSeverity(c.KeepLevel))
+	}
+	if c.Encoding == "console" && !c.IsSave {
+		opts = append(opts, WithColor(c.Color))
+	}
+	if len(c.FieldOrder) > 0 {
+		opts = append(opts, WithFieldOrder(c.FieldOrder...))
+	}
+	if c.SortedFields {
+		opts = append(opts, WithSortedFields())
+	}
+	if c.InitialFields != nil {
+		opts = append(opts, WithInitialFields(c.InitialFields))
+	}
+	if c.MessagePrefix != "" {
+		opts = append(opts, WithMessagePrefix(c.MessagePrefix))
+	}
+	if c.CompactInts > 0 {
+		opts = append(opts, WithCompactInts(c.CompactInts))
+	}
+	if c.SchemaVersion != "" {
+		opts = append(opts, WithSchemaVersion(c.SchemaVersion))
+	}
+	if c.ProcessInfo {
+		opts = append(opts, WithProcessInfo())
+	}
+	if c.EpochField != "" {
+		opts = append(opts, WithEpochField(c.EpochField))
+	}
+	if c.StacktraceTrim {
+		opts = append(opts, WithStacktraceTrim(c.StacktraceTrimPrefixes...))
+	}
+	if c.LargeAnyLimit > 0 {
+		opts = append(opts, WithLargeAnyWarning(c.LargeAnyLimit))
+	}
+
+	if c.SamplingTick > 0 {
+		opts = append(opts, WithSampling(c.SamplingTick, c.SamplingFirst, c.SamplingThereafter))
+	}
+	if c.SamplingKey != "" {
+		opts = append(opts, WithSamplingKey(c.SamplingKey))
+	}
+	if c.RateLimit > 0 {
+		opts = append(opts, WithRateLimit(c.RateLimit))
+	}
+	if c.EventLogSource != "" {
+		opts = append(opts, WithEventLog(c.EventLogSource))
+	}
+	if c.Journald {
+		opts = append(opts, WithJournald())
+	}
+	if c.WriteTimeout > 0 {
+		opts = append(opts, WithWriteTimeout(c.WriteTimeout))
+	}
+	if c.Fallback || c.FallbackFilename != "" {
+		opts = append(opts, WithFallback(c.FallbackFilename))
+	}
+	if c.AuditFilename != "" {
+		opts = append(opts, WithAudit(c.AuditFilename))
+	}
+	return opts
+}
+
+// 根据选项和解析后的zap配置生成生效配置
+func (o *options) effectiveConfig(config zap.Config) Config {
+	cfg := Config{
+		Level:    config.Level.String(),
+		Encoding: config.Encoding,
+		IsSave:   o.isSave,
+		Stderr:   o.splitStreams(),
+
+		FullCaller:       o.fullCaller,
+		CallerTrimPrefix: o.callerTrimPrefix,
+		CallerFields:     o.callerFields,
+		ModuleName:       o.moduleName,
+
+		ShortLevels:   o.shortLevels && o.isConsole(),
+		UTC:           o.utc,
+		Severity:      o.severity,
+		KeepLevel:     o.severity && o.keepLevel,
+		Color:         o.isConsole() && o.colorEnabled(),
+		FieldOrder:    o.fieldOrder,
+		SortedFields:  o.sortFields && o.isConsole(),
+		InitialFields: o.initialFields,
+		MessagePrefix: o.messagePrefix,
+		CompactInts:   o.compactInts,
+		ProcessInfo:   o.processInfo,
+		EpochField:    o.epochField,
+
+		StacktraceTrim:         o.trimStack,
+		StacktraceTrimPrefixes: o.stackTrim,
+
+		LargeAnyLimit: o.largeAnyLimit,
+
+		SamplingKey: o.samplingKey,
+		RateLimit:   o.rateLimit,
+
+		TeeCount:       len(o.tees),
+		EventLogSource: o.eventLogSource,
+		CoreCount:      len(o.cores),
+		Journald:       o.journald,
+		WriteTimeout:   o.writeTimeout,
+		Fallback:       o.fallback,
+		AuditFilename:  o.auditFilename,
+	}
+	if o.isSave && len(config.OutputPaths) > 0 {
+		cfg.Filename = config.OutputPaths[0]
+	}
+	if o.isSave && o.rotation == nil {
+		cfg.FailoverFilenames = o.failoverFiles
+	}
+	if o.isSave && o.rotation != nil {
+		cfg.RotationMode = o.rotation.mode
+		switch o.rotation.mode {
+		case RotationSize:
+			cfg.RotationMaxSize = o.rotation.maxSize
+			if cfg.RotationMaxSize <= 0 {
+				cfg.RotationMaxSize = defaultMaxSize
+			}
+		case RotationTime:
+			cfg.RotationInterval, cfg.RotationPattern = o.rotation.interval, o.rotation.pattern
+			if cfg.RotationInterval == 0 {
+				cfg.RotationInterval = defaultRotationInterval
+			}
+			if cfg.RotationPattern == "" {
+				cfg.RotationPattern = defaultRotationPattern
+			}
+		}
+	}
+	if o.sampling != nil {
+		cfg.SamplingTick, cfg.SamplingFirst, cfg.SamplingThereafter = o.sampling.tick, o.sampling.first, o.sampling.thereafter
+	}
+	if o.fallback {
+		cfg.FallbackFilename = o.fallbackFilename
+	}
+	if o.schemaField != nil {
+		cfg.SchemaVersion = o.schemaField.String
+	}
+	return cfg
+}
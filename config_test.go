@@ -0,0 +1,102 @@
+package logger
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEffectiveConfig(t *testing.T) {
+	filename := initTestLogger(t, WithLogLevel("warn"), WithCallerFields(), WithSampling(time.Second, 10, 5))
+
+	cfg := EffectiveConfig()
+	if cfg.Level != "warn" || cfg.Encoding != "json" || !cfg.IsSave || cfg.Filename != filename {
+		t.Errorf("unexpected base config %+v", cfg)
+	}
+	if !cfg.CallerFields || cfg.SamplingTick != time.Second || cfg.SamplingFirst != 10 || cfg.SamplingThereafter != 5 {
+		t.Errorf("unexpected option config %+v", cfg)
+	}
+}
+
+func TestCurrentConfigOptions(t *testing.T) {
+	initTestLogger(t, WithLogLevel("info"), WithCallerTrimPrefix("/src/"), WithSeverity(true), WithUTC(),
+		WithInitialFields(map[string]interface{}{"service": "api"}), WithSampling(time.Second, 10, 5))
+
+	cfg := CurrentConfig()
+	cfg.Filename = filepath.Join(t.TempDir(), "sub.log")
+	if err := Init(cfg.Options()...); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := CurrentConfig(); !reflect.DeepEqual(got, cfg) {
+		t.Errorf("expected derived config %+v, got %+v", cfg, got)
+	}
+	Info("derived")
+	if entry := findLogLine(readLogLines(t, cfg.Filename), "derived"); entry == nil || entry["service"] != "api" {
+		t.Errorf("expected entry with initial fields in derived file, got %v", entry)
+	}
+}
+
+func TestConfigOptionsRoundTrip(t *testing.T) {
+	initTestLogger(t, WithSchemaVersion("v2"), WithProcessInfo(), WithEpochField("ts_ms"), WithStacktraceTrim("net/http"),
+		WithLargeAnyWarning(1024), WithSampling(time.Second, 10, 5), WithSamplingKey("request_id"),
+		WithRateLimit(100), WithWriteTimeout(time.Second), WithFallback(""))
+
+	cfg := CurrentConfig()
+	if cfg.SchemaVersion != "v2" || !cfg.ProcessInfo || cfg.EpochField != "ts_ms" || !cfg.StacktraceTrim || cfg.LargeAnyLimit != 1024 ||
+		cfg.SamplingKey != "request_id" || cfg.RateLimit != 100 || cfg.WriteTimeout != time.Second || !cfg.Fallback {
+		t.Errorf("unexpected config %+v", cfg)
+	}
+
+	cfg.Filename = filepath.Join(t.TempDir(), "sub.log")
+	if err := Init(cfg.Options()...); err != nil {
+		t.Fatal(err)
+	}
+	if got := CurrentConfig(); !reflect.DeepEqual(got, cfg) {
+		t.Errorf("expected derived config %+v, got %+v", cfg, got)
+	}
+}
+
+func TestPrintConfig(t *testing.T) {
+	filename := initTestLogger(t, WithLogLevel("info"), WithCallerFields(), WithSampling(time.Second, 10, 5))
+	PrintConfig()
+
+	entry := findLogLine(readLogLines(t, filename), "logger config")
+	if entry == nil {
+		t.Fatal("expected config entry")
+	}
+	if entry["level"] != "info" || entry["log_level"] != "info" || entry["encoding"] != "json" || entry["caller_fields"] != true || entry["stacktrace_level"] != "error" {
+		t.Errorf("unexpected config entry %v", entry)
+	}
+	if outputs, _ := entry["outputs"].([]interface{}); len(outputs) != 1 || outputs[0] != filename {
+		t.Errorf("expected outputs [%s], got %v", filename, entry["outputs"])
+	}
+	if entry["sampling_first"] != float64(10) {
+		t.Errorf("expected sampling settings, got %v", entry)
+	}
+	if _, ok := entry["audit_filename"]; ok {
+		t.Errorf("expected unset settings to be omitted, got %v", entry)
+	}
+}
+
+func TestPrintConfigAboveLevel(t *testing.T) {
+	filename := initTestLogger(t, WithLogLevel("warn"))
+	PrintConfig()
+	Info("filtered info")
+
+	lines := readLogLines(t, filename)
+	if entry := findLogLine(lines, "logger config"); entry == nil || entry["log_level"] != "warn" {
+		t.Errorf("expected config entry regardless of level, got %v", entry)
+	}
+	if findLogLine(lines, "filtered info") != nil {
+		t.Error("expected info entries to still be filtered")
+	}
+}
+
+func TestEffectiveConfigRotation(t *testing.T) {
+	initTestLogger(t, WithRotationMode(RotationSize))
+	if cfg := EffectiveConfig(); cfg.RotationMode != RotationSize || cfg.RotationMaxSize != defaultMaxSize {
+		t.Errorf("unexpected rotation config %+v", cfg)
+	}
+}
@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 最外层的core，统一判断日志级别，内部的core(输出、采样、hook等)不再判断全局日志级别，
+// 方便Ctx按请求替换日志级别
+type rootCore struct {
+	zapcore.Core
+	enab zapcore.LevelEnabler
+}
+
+func (c *rootCore) Enabled(level zapcore.Level) bool {
+	return c.enab.Enabled(level)
+}
+
+func (c *rootCore) With(fields []Field) zapcore.Core {
+	return &rootCore{Core: c.Core.With(fields), enab: c.enab}
+}
+
+func (c *rootCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.enab.Enabled(ent.Level) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// 在rootCore内部包装core，保证rootCore仍然在最外层
+func wrapInner(fn func(zapcore.Core) zapcore.Core) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if rc, ok := core.(*rootCore); ok {
+			return &rootCore{Core: fn(rc.Core), enab: rc.enab}
+		}
+		return fn(core)
+	})
+}
+
+// 替换rootCore的日志级别判断
+func replaceLevel(fn func(zapcore.LevelEnabler) zapcore.LevelEnabler) zap.Option {
+	return zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		if rc, ok := core.(*rootCore); ok {
+			return &rootCore{Core: rc.Core, enab: fn(rc.enab)}
+		}
+		return core
+	})
+}
+
+// 写入前处理日志的函数，返回false表示丢弃
+type interceptFunc func(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool)
+
+// 在写入前处理日志的core，通过被包装core的Check确定要写入的core，保证采样、hook等在Check中生效的逻辑不被跳过
+type interceptCore struct {
+	zapcore.Core
+	fn interceptFunc
+}
+
+func (c *interceptCore) With(fields []Field) zapcore.Core {
+	return &interceptCore{Core: c.Core.With(fields), fn: c.fn}
+}
+
+func (c *interceptCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+	return ce.AddCore(ent, &checkedWriter{inner: inner, fn: c.fn})
+}
+
+func (c *interceptCore) Write(ent zapcore.Entry, fields []Field) error {
+	ent, fields, ok := c.fn(ent, fields)
+	if !ok {
+		return nil
+	}
+	return c.Core.Write(ent, fields)
+}
+
+// 处理日志后写入已经Check过的core，fn为nil时直接写入
+type checkedWriter struct {
+	inner *zapcore.CheckedEntry
+	fn    interceptFunc
+}
+
+func (w *checkedWriter) Enabled(zapcore.Level) bool { return true }
+
+func (w *checkedWriter) With([]Field) zapcore.Core { return w }
+
+func (w *checkedWriter) Check(_ zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce
+}
+
+func (w *checkedWriter) Write(ent zapcore.Entry, fields []Field) error {
+	if w.fn != nil {
+		var ok bool
+		if ent, fields, ok = w.fn(ent, fields); !ok {
+			return nil
+		}
+	}
+	// 内部CheckedEntry把写入错误输出到ErrorOutput，收集后返回给外层
+	errs := &writeErrors{}
+	w.inner.Entry = ent
+	w.inner.ErrorOutput = errs
+	w.inner.Write(fields...)
+	return errs.err
+}
+
+func (w *checkedWriter) Sync() error { return nil }
+
+// 收集CheckedEntry写入错误的ErrorOutput
+type writeErrors struct {
+	err error
+}
+
+func (e *writeErrors) Write(p []byte) (int, error) {
+	// 格式为"<时间> write error: <错误>"
+	msg := strings.TrimSpace(string(p))
+	if _, after, ok := strings.Cut(msg, " write error: "); ok {
+		msg = after
+	}
+	e.err = errors.Join(e.err, errors.New(msg))
+	return len(p), nil
+}
+
+func (e *writeErrors) Sync() error { return nil }
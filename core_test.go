@@ -0,0 +1,24 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestInterceptCoreWriteError(t *testing.T) {
+	sink := &flakyWriter{down: true}
+	inner := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), sink, zapcore.DebugLevel)
+	core := &interceptCore{Core: inner, fn: func(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+		return ent, fields, true
+	}}
+
+	errOut := &bytes.Buffer{}
+	zap.New(core, zap.ErrorOutput(zapcore.AddSync(errOut))).Info("write fails")
+	if !strings.Contains(errOut.String(), "sink unavailable") {
+		t.Errorf("expected sink write error to be reported, got %q", errOut.String())
+	}
+}
@@ -0,0 +1,63 @@
+package logger
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// CtxStatus context已取消或超时时输出原因ctx_err，有截止时间时同时输出距离截止时间的剩余时间deadline_in，
+// 已超过截止时间为"expired"，context正常或为nil时不输出任何字段
+//
+//	eg: Warn("request aborted", CtxStatus(ctx))
+func CtxStatus(ctx context.Context) Field {
+	if ctx == nil {
+		return zap.Skip()
+	}
+	err := ctx.Err()
+	if err == nil {
+		return zap.Skip()
+	}
+
+	status := ctxStatus{err: err.Error()}
+	if _, ok := ctx.Deadline(); ok {
+		status.deadlineIn = deadlineIn(ctx)
+	}
+	return zap.Inline(status)
+}
+
+// Deadline 输出距离context截止时间的剩余时间deadline_in，已超过截止时间为"expired"，没有截止时间或ctx为nil时为"no-deadline"
+//
+//	eg: Info("call downstream", Deadline(ctx))
+func Deadline(ctx context.Context) Field {
+	return zap.String("deadline_in", deadlineIn(ctx))
+}
+
+func deadlineIn(ctx context.Context) string {
+	if ctx == nil {
+		return "no-deadline"
+	}
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return "no-deadline"
+	}
+	if d := time.Until(deadline); d > 0 {
+		return d.String()
+	}
+	return "expired"
+}
+
+type ctxStatus struct {
+	err        string
+	deadlineIn string
+}
+
+func (s ctxStatus) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("ctx_err", s.err)
+	if s.deadlineIn != "" {
+		enc.AddString("deadline_in", s.deadlineIn)
+	}
+	return nil
+}
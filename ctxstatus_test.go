@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestCtxStatus(t *testing.T) {
+	filename := initTestLogger(t)
+
+	active, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	Info("active ctx", CtxStatus(active))
+
+	cancelled, cancel := context.WithTimeout(context.Background(), time.Hour)
+	cancel()
+	Info("cancelled ctx", CtxStatus(cancelled))
+
+	expired, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-expired.Done()
+	Info("expired ctx", CtxStatus(expired))
+
+	lines := readLogLines(t, filename)
+	if entry := findLogLine(lines, "active ctx"); entry["ctx_err"] != nil || entry["deadline_in"] != nil {
+		t.Errorf("expected no ctx status fields, got %v", entry)
+	}
+	entry := findLogLine(lines, "cancelled ctx")
+	if entry["ctx_err"] != "context canceled" {
+		t.Errorf("expected ctx_err for cancelled ctx, got %v", entry)
+	}
+	deadlineIn, _ := entry["deadline_in"].(string)
+	if d, err := time.ParseDuration(deadlineIn); err != nil || d <= 0 {
+		t.Errorf("expected remaining deadline_in, got %v", entry["deadline_in"])
+	}
+	entry = findLogLine(lines, "expired ctx")
+	if entry["ctx_err"] != "context deadline exceeded" || entry["deadline_in"] != "expired" {
+		t.Errorf("expected expired ctx status, got %v", entry)
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	filename := initTestLogger(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	Info("with deadline", Deadline(ctx))
+	Info("without deadline", Deadline(context.Background()))
+
+	lines := readLogLines(t, filename)
+	deadlineIn, _ := findLogLine(lines, "with deadline")["deadline_in"].(string)
+	if d, err := time.ParseDuration(deadlineIn); err != nil || d <= 0 || d > time.Hour {
+		t.Errorf("expected remaining deadline_in, got %q", deadlineIn)
+	}
+	if entry := findLogLine(lines, "without deadline"); entry["deadline_in"] != "no-deadline" {
+		t.Errorf("expected no-deadline, got %v", entry["deadline_in"])
+	}
+}
+
+func TestCtxStatusNil(t *testing.T) {
+	var ctx context.Context // 与Ctx一样允许nil
+	if m := FieldsToMap(CtxStatus(ctx)); len(m) != 0 {
+		t.Errorf("expected no fields for nil ctx, got %v", m)
+	}
+	if got := FieldsToMap(Deadline(ctx))["deadline_in"]; got != "no-deadline" {
+		t.Errorf("expected no-deadline for nil ctx, got %v", got)
+	}
+}
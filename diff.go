@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"reflect"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Diff 比较两个相同类型的结构体(或结构体指针)，只输出有变化的导出字段，格式为{字段名: {from, to}}，
+// 不导出的字段被忽略，old或new为nil时按零值比较，类型不同或者不是结构体时输出{from, to}
+func Diff(key string, old, new interface{}) Field {
+	oldVal, newVal := indirectValue(old), indirectValue(new)
+	switch {
+	case !oldVal.IsValid() && !newVal.IsValid():
+		return zap.Object(key, structDiff(nil))
+	case !oldVal.IsValid():
+		oldVal = reflect.Zero(newVal.Type())
+	case !newVal.IsValid():
+		newVal = reflect.Zero(oldVal.Type())
+	}
+
+	if oldVal.Type() != newVal.Type() || oldVal.Kind() != reflect.Struct {
+		return zap.Object(key, fromTo{from: old, to: new})
+	}
+
+	var diffs structDiff
+	t := oldVal.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		from, to := oldVal.Field(i).Interface(), newVal.Field(i).Interface()
+		if !reflect.DeepEqual(from, to) {
+			diffs = append(diffs, fieldDiff{name: t.Field(i).Name, fromTo: fromTo{from: from, to: to}})
+		}
+	}
+	return zap.Object(key, diffs)
+}
+
+// 解引用指针，nil返回无效值
+func indirectValue(v interface{}) reflect.Value {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return reflect.Value{}
+		}
+		val = val.Elem()
+	}
+	return val
+}
+
+type fromTo struct {
+	from, to interface{}
+}
+
+func (f fromTo) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	if err := enc.AddReflected("from", f.from); err != nil {
+		return err
+	}
+	return enc.AddReflected("to", f.to)
+}
+
+type fieldDiff struct {
+	name string
+	fromTo
+}
+
+type structDiff []fieldDiff
+
+func (d structDiff) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, f := range d {
+		if err := enc.AddObject(f.name, f.fromTo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
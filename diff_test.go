@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+type account struct {
+	Name    string
+	Balance int
+	Tags    []string
+	secret  string
+}
+
+func TestDiff(t *testing.T) {
+	filename := initTestLogger(t)
+
+	old := &account{Name: "foo", Balance: 10, Tags: []string{"a"}, secret: "x"}
+	updated := &account{Name: "foo", Balance: 20, Tags: []string{"a"}, secret: "y"}
+	Info("account updated", Diff("diff", old, updated))
+	Info("account created", Diff("diff", nil, &account{Name: "bar"}))
+	Info("nothing", Diff("diff", nil, nil))
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`"msg":"account updated","diff":{"Balance":{"from":10,"to":20}}}`,
+		`"msg":"account created","diff":{"Name":{"from":"","to":"bar"}}}`,
+		`"msg":"nothing","diff":{}}`,
+	} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in %s", want, data)
+		}
+	}
+}
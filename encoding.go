@@ -0,0 +1,182 @@
+package logger
+
+import (
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// 当前logger的输出格式开关
+var activeEncoding atomic.Pointer[encodingSwitch]
+
+// SetEncoding 运行时切换当前logger的输出格式，encoding为json或console，日志级别、输出和已添加的字段不变，
+// 例如排查问题时把json格式切换为console格式直接查看，不需要重启服务，logfmt格式不支持切换
+func SetEncoding(encoding string) error {
+	if encoding != "json" && encoding != "console" {
+		return errors.New("encoding must be json or console")
+	}
+
+	loadLogger()
+	s := activeEncoding.Load()
+	if s == nil {
+		return errors.New("logger does not support switching encoding")
+	}
+	s.json.Store(encoding == "json")
+
+	loggerMu.Lock()
+	effectiveConfig.Encoding = encoding
+	loggerMu.Unlock()
+	return nil
+}
+
+// 输出格式开关，同一个logger的所有encoder共用
+type encodingSwitch struct {
+	json atomic.Bool
+}
+
+// 同时维护json和console两个encoder，字段同时添加到两个encoder，输出时根据开关选择encoder
+type switchEncoder struct {
+	mode    *encodingSwitch
+	json    zapcore.Encoder
+	console zapcore.Encoder
+}
+
+func (e *switchEncoder) current() zapcore.Encoder {
+	if e.mode.json.Load() {
+		return e.json
+	}
+	return e.console
+}
+
+func (e *switchEncoder) Clone() zapcore.Encoder {
+	return &switchEncoder{mode: e.mode, json: e.json.Clone(), console: e.console.Clone()}
+}
+
+func (e *switchEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	return e.current().EncodeEntry(ent, fields)
+}
+
+func (e *switchEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	return errors.Join(e.json.AddArray(key, v), e.console.AddArray(key, v))
+}
+
+func (e *switchEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	return errors.Join(e.json.AddObject(key, v), e.console.AddObject(key, v))
+}
+
+func (e *switchEncoder) AddReflected(key string, v interface{}) error {
+	return errors.Join(e.json.AddReflected(key, v), e.console.AddReflected(key, v))
+}
+
+func (e *switchEncoder) AddBinary(key string, v []byte) {
+	e.json.AddBinary(key, v)
+	e.console.AddBinary(key, v)
+}
+
+func (e *switchEncoder) AddByteString(key string, v []byte) {
+	e.json.AddByteString(key, v)
+	e.console.AddByteString(key, v)
+}
+
+func (e *switchEncoder) AddBool(key string, v bool) {
+	e.json.AddBool(key, v)
+	e.console.AddBool(key, v)
+}
+
+func (e *switchEncoder) AddComplex128(key string, v complex128) {
+	e.json.AddComplex128(key, v)
+	e.console.AddComplex128(key, v)
+}
+
+func (e *switchEncoder) AddComplex64(key string, v complex64) {
+	e.json.AddComplex64(key, v)
+	e.console.AddComplex64(key, v)
+}
+
+func (e *switchEncoder) AddDuration(key string, v time.Duration) {
+	e.json.AddDuration(key, v)
+	e.console.AddDuration(key, v)
+}
+
+func (e *switchEncoder) AddFloat64(key string, v float64) {
+	e.json.AddFloat64(key, v)
+	e.console.AddFloat64(key, v)
+}
+
+func (e *switchEncoder) AddFloat32(key string, v float32) {
+	e.json.AddFloat32(key, v)
+	e.console.AddFloat32(key, v)
+}
+
+func (e *switchEncoder) AddInt(key string, v int) {
+	e.json.AddInt(key, v)
+	e.console.AddInt(key, v)
+}
+
+func (e *switchEncoder) AddInt64(key string, v int64) {
+	e.json.AddInt64(key, v)
+	e.console.AddInt64(key, v)
+}
+
+func (e *switchEncoder) AddInt32(key string, v int32) {
+	e.json.AddInt32(key, v)
+	e.console.AddInt32(key, v)
+}
+
+func (e *switchEncoder) AddInt16(key string, v int16) {
+	e.json.AddInt16(key, v)
+	e.console.AddInt16(key, v)
+}
+
+func (e *switchEncoder) AddInt8(key string, v int8) {
+	e.json.AddInt8(key, v)
+	e.console.AddInt8(key, v)
+}
+
+func (e *switchEncoder) AddString(key, v string) {
+	e.json.AddString(key, v)
+	e.console.AddString(key, v)
+}
+
+func (e *switchEncoder) AddTime(key string, v time.Time) {
+	e.json.AddTime(key, v)
+	e.console.AddTime(key, v)
+}
+
+func (e *switchEncoder) AddUint(key string, v uint) {
+	e.json.AddUint(key, v)
+	e.console.AddUint(key, v)
+}
+
+func (e *switchEncoder) AddUint64(key string, v uint64) {
+	e.json.AddUint64(key, v)
+	e.console.AddUint64(key, v)
+}
+
+func (e *switchEncoder) AddUint32(key string, v uint32) {
+	e.json.AddUint32(key, v)
+	e.console.AddUint32(key, v)
+}
+
+func (e *switchEncoder) AddUint16(key string, v uint16) {
+	e.json.AddUint16(key, v)
+	e.console.AddUint16(key, v)
+}
+
+func (e *switchEncoder) AddUint8(key string, v uint8) {
+	e.json.AddUint8(key, v)
+	e.console.AddUint8(key, v)
+}
+
+func (e *switchEncoder) AddUintptr(key string, v uintptr) {
+	e.json.AddUintptr(key, v)
+	e.console.AddUintptr(key, v)
+}
+
+func (e *switchEncoder) OpenNamespace(key string) {
+	e.json.OpenNamespace(key)
+	e.console.OpenNamespace(key)
+}
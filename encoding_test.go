@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSetEncoding(t *testing.T) {
+	if err := Init(WithEncoding("json"), WithColor(false)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetLogger(t) })
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	logger := WithFields(String("svc", "api"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				logger.Debug("concurrent")
+			}
+		}()
+	}
+	if err := SetEncoding("console"); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	buf.Reset()
+
+	logger.Info("console entry", Int("n", 1))
+	if got := buf.String(); !strings.Contains(got, "\tinfo\t") || !strings.Contains(got, `{"svc": "api", "n": 1}`) {
+		t.Errorf("expected console entry with fields, got %q", got)
+	}
+	if EffectiveConfig().Encoding != "console" {
+		t.Errorf("expected console encoding in config, got %s", EffectiveConfig().Encoding)
+	}
+
+	buf.Reset()
+	if err := SetEncoding("json"); err != nil {
+		t.Fatal(err)
+	}
+	logger.Info("json entry")
+	if got := buf.String(); !strings.Contains(got, `"msg":"json entry","svc":"api"`) {
+		t.Errorf("expected json entry with fields, got %q", got)
+	}
+
+	if err := SetEncoding("xml"); err == nil {
+		t.Error("expected error for unknown encoding")
+	}
+}
+
+func TestSetEncodingLogfmt(t *testing.T) {
+	if err := Init(WithEncoding("logfmt")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetLogger(t) })
+	if err := SetEncoding("console"); err == nil {
+		t.Error("expected error for logfmt encoding")
+	}
+}
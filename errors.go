@@ -0,0 +1,116 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WrapErr 输出error级别日志并返回包装后的错误，包装后的错误保留errors.Is/As的判断，err为nil时返回nil，
+//
+//	eg: return WrapErr(err, "save user", String("name", name))
+func WrapErr(err error, msg string, fields ...Field) error {
+	if err == nil {
+		return nil
+	}
+
+	getLogger().Error(msg, append(fields[:len(fields):len(fields)], Err(err))...)
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+// LogErr 输出error级别日志并原样返回err，err为nil时返回nil，
+//
+//	eg: return LogErr("failed to save", err, String("name", name))
+func LogErr(msg string, err error, fields ...Field) error {
+	if err == nil {
+		return nil
+	}
+
+	getLogger().Error(msg, append(fields[:len(fields):len(fields)], Err(err))...)
+	return err
+}
+
+// CodedError 输出error级别日志，携带错误码字段error_code和错误字段error，方便日志后端按错误码告警，
+//
+//	eg: CodedError("ORDER_NOT_FOUND", "query order failed", err, String("order_id", id))
+func CodedError(code, msg string, err error, fields ...Field) {
+	getLogger().Error(msg, append(fields[:len(fields):len(fields)], String("error_code", code), zap.Error(err))...)
+}
+
+// WrapCode 给err附加错误码code，使用Err(err)输出时同时输出error_code字段，err为nil时返回nil
+func WrapCode(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedErr{code: code, err: err}
+}
+
+// ErrorCode 返回err及其包装的错误中通过WrapCode附加的错误码，没有时返回空字符串
+func ErrorCode(err error) string {
+	var ce *codedErr
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return ""
+}
+
+type codedErr struct {
+	code string
+	err  error
+}
+
+func (e *codedErr) Error() string { return e.err.Error() }
+
+func (e *codedErr) Unwrap() error { return e.err }
+
+// 同时输出error和error_code字段
+type codedErrField struct {
+	code string
+	err  error
+}
+
+func (f codedErrField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("error", f.err.Error())
+	enc.AddString("error_code", f.code)
+	return nil
+}
+
+var (
+	errorCodes   = make(map[string]string)
+	errorCodesMu sync.RWMutex
+)
+
+// RegisterErrorCode 注册错误码code对应的说明message，ErrCode输出时使用，重复注册时覆盖，一般在init中注册
+//
+//	eg: RegisterErrorCode("E1001", "order not found")
+func RegisterErrorCode(code, message string) {
+	errorCodesMu.Lock()
+	errorCodes[code] = message
+	errorCodesMu.Unlock()
+}
+
+// ErrCode 错误码字段err_code，输出为{"code":"E1001","message":"order not found"}，
+// message为RegisterErrorCode注册的说明，未注册的错误码输出"unknown"
+func ErrCode(code string) Field {
+	errorCodesMu.RLock()
+	message, ok := errorCodes[code]
+	errorCodesMu.RUnlock()
+	if !ok {
+		message = "unknown"
+	}
+	return zap.Object("err_code", errCodeField{code: code, message: message})
+}
+
+type errCodeField struct {
+	code    string
+	message string
+}
+
+func (f errCodeField) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("code", f.code)
+	enc.AddString("message", f.message)
+	return nil
+}
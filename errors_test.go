@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWrapErr(t *testing.T) {
+	filename := initTestLogger(t)
+
+	err := WrapErr(io.EOF, "read body", String("path", "/users"))
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("expected wrapped error to match io.EOF, got %v", err)
+	}
+	if err.Error() != "read body: EOF" {
+		t.Errorf("unexpected error message %q", err.Error())
+	}
+	if WrapErr(nil, "nothing") != nil {
+		t.Error("expected nil for nil error")
+	}
+	fields := make([]Field, 1, 2)
+	fields[0] = String("path", "/users")
+	_ = WrapErr(io.EOF, "spare capacity", fields...)
+	if fields[:2][1].Key != "" {
+		t.Errorf("expected caller's field slice to be untouched, got %v", fields[:2])
+	}
+
+	entry := findLogLine(readLogLines(t, filename), "read body")
+	if entry == nil || entry["level"] != "error" || entry["error"] != "EOF" || entry["path"] != "/users" {
+		t.Errorf("unexpected log entry %v", entry)
+	}
+}
+
+func TestLogErr(t *testing.T) {
+	filename := initTestLogger(t)
+
+	err := LogErr("failed to save", io.ErrUnexpectedEOF, String("name", "foo"))
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("expected the same error, got %v", err)
+	}
+	if LogErr("nothing", nil) != nil {
+		t.Error("expected nil for nil error")
+	}
+	fields := make([]Field, 1, 2)
+	fields[0] = String("name", "foo")
+	_ = LogErr("spare capacity", io.EOF, fields...)
+	if fields[:2][1].Key != "" {
+		t.Errorf("expected caller's field slice to be untouched, got %v", fields[:2])
+	}
+
+	entry := findLogLine(readLogLines(t, filename), "failed to save")
+	if entry == nil || entry["level"] != "error" || entry["error"] != "unexpected EOF" || entry["name"] != "foo" {
+		t.Errorf("unexpected log entry %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "errors_test.go") {
+		t.Errorf("expected caller in errors_test.go, got %v", entry["caller"])
+	}
+}
+
+func TestCodedError(t *testing.T) {
+	filename := initTestLogger(t)
+	CodedError("ORDER_NOT_FOUND", "query order failed", io.EOF, String("order_id", "o-1"))
+	fields := make([]Field, 1, 3)
+	fields[0] = String("order_id", "o-2")
+	CodedError("ORDER_NOT_FOUND", "spare capacity", io.EOF, fields...)
+	if fields[:3][1].Key != "" || fields[:3][2].Key != "" {
+		t.Errorf("expected caller's field slice to be untouched, got %v", fields[:3])
+	}
+
+	err := fmt.Errorf("save order: %w", WrapCode("DB_TIMEOUT", io.ErrUnexpectedEOF))
+	Warn("wrapped code", Err(err))
+	if ErrorCode(err) != "DB_TIMEOUT" || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected error code and wrapped error, got %q", ErrorCode(err))
+	}
+	if WrapCode("X", nil) != nil || ErrorCode(io.EOF) != "" {
+		t.Error("expected no code for nil or plain errors")
+	}
+
+	lines := readLogLines(t, filename)
+	entry := findLogLine(lines, "query order failed")
+	if entry == nil || entry["level"] != "error" || entry["error_code"] != "ORDER_NOT_FOUND" || entry["error"] != "EOF" || entry["order_id"] != "o-1" {
+		t.Errorf("unexpected coded error entry %v", entry)
+	}
+	entry = findLogLine(lines, "wrapped code")
+	if entry["error_code"] != "DB_TIMEOUT" || entry["error"] != "save order: unexpected EOF" {
+		t.Errorf("expected error_code from Err, got %v", entry)
+	}
+}
+
+func TestErrCode(t *testing.T) {
+	RegisterErrorCode("E1001", "order not found")
+	m := FieldsToMap(ErrCode("E1001"))
+	if got, _ := m["err_code"].(map[string]interface{}); got["code"] != "E1001" || got["message"] != "order not found" {
+		t.Errorf("unexpected registered code field %v", m["err_code"])
+	}
+
+	m = FieldsToMap(ErrCode("E9999"))
+	if got, _ := m["err_code"].(map[string]interface{}); got["code"] != "E9999" || got["message"] != "unknown" {
+		t.Errorf("unexpected unknown code field %v", m["err_code"])
+	}
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package logger
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newEventLogCore(string, zapcore.Encoder, zapcore.LevelEnabler) (zapcore.Core, error) {
+	return nil, errors.New("windows event log is only supported on windows")
+}
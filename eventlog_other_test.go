@@ -0,0 +1,11 @@
+//go:build !windows
+
+package logger
+
+import "testing"
+
+func TestWithEventLogUnsupported(t *testing.T) {
+	if err := Init(WithEventLog("logger-test")); err == nil {
+		t.Error("expected error on non-windows system")
+	}
+}
@@ -0,0 +1,67 @@
+//go:build windows
+
+package logger
+
+import (
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+const eventLogID = 1
+
+// 写入Windows事件日志的core，日志级别映射为事件类型Info/Warning/Error
+type eventLogCore struct {
+	zapcore.LevelEnabler
+	encoder zapcore.Encoder
+	log     *eventlog.Log
+}
+
+func newEventLogCore(source string, encoder zapcore.Encoder, enab zapcore.LevelEnabler) (zapcore.Core, error) {
+	l, err := eventlog.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	return &eventLogCore{LevelEnabler: enab, encoder: encoder, log: l}, nil
+}
+
+func (c *eventLogCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := &eventLogCore{LevelEnabler: c.LevelEnabler, encoder: c.encoder.Clone(), log: c.log}
+	for _, field := range fields {
+		field.AddTo(clone.encoder)
+	}
+	return clone
+}
+
+func (c *eventLogCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *eventLogCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	msg := buf.String()
+	buf.Free()
+
+	switch {
+	case ent.Level >= zapcore.ErrorLevel:
+		return c.log.Error(eventLogID, msg)
+	case ent.Level == zapcore.WarnLevel:
+		return c.log.Warning(eventLogID, msg)
+	default:
+		return c.log.Info(eventLogID, msg)
+	}
+}
+
+func (c *eventLogCore) Sync() error {
+	return nil
+}
+
+// 关闭事件日志句柄，With派生的core共用同一个句柄
+func (c *eventLogCore) Close() error {
+	return c.log.Close()
+}
@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// Logger 分级日志接口，全局logger(Default)和AsLogger包装的logger都实现了该接口，
+// 库可以依赖Logger接口而不是全局logger，方便注入和测试
+type Logger interface {
+	Debug(msg string, fields ...Field)
+	Info(msg string, fields ...Field)
+	Warn(msg string, fields ...Field)
+	Error(msg string, fields ...Field)
+	With(fields ...Field) Logger
+	Named(name string) Logger
+	Ctx(ctx context.Context) Logger
+}
+
+// Default 返回使用全局logger的Logger，重新初始化全局logger后仍然有效
+func Default() Logger {
+	return globalLogger{}
+}
+
+// AsLogger 把zap logger包装为Logger，例如NewLoggerWithEncoder返回的logger
+//
+//	eg: lib.New(logger.AsLogger(zapLogger))
+func AsLogger(l *ZapLogger) Logger {
+	return zapLogger{l: l.WithOptions(zap.AddCallerSkip(1))}
+}
+
+// 使用全局logger，每次调用时获取当前的全局logger
+type globalLogger struct{}
+
+func (globalLogger) Debug(msg string, fields ...Field) { getLogger().Debug(msg, fields...) }
+func (globalLogger) Info(msg string, fields ...Field)  { getLogger().Info(msg, fields...) }
+func (globalLogger) Warn(msg string, fields ...Field)  { getLogger().Warn(msg, fields...) }
+func (globalLogger) Error(msg string, fields ...Field) { getLogger().Error(msg, fields...) }
+
+func (globalLogger) With(fields ...Field) Logger {
+	return AsLogger(loadLogger().With(fields...))
+}
+
+func (globalLogger) Named(name string) Logger {
+	return AsLogger(loadLogger().Named(name))
+}
+
+func (globalLogger) Ctx(ctx context.Context) Logger {
+	return AsLogger(ctxLogger(loadLogger(), ctx))
+}
+
+// 包装zap logger，l已经跳过一层调用
+type zapLogger struct {
+	l *zap.Logger
+}
+
+func (z zapLogger) Debug(msg string, fields ...Field) { z.l.Debug(msg, fields...) }
+func (z zapLogger) Info(msg string, fields ...Field)  { z.l.Info(msg, fields...) }
+func (z zapLogger) Warn(msg string, fields ...Field)  { z.l.Warn(msg, fields...) }
+func (z zapLogger) Error(msg string, fields ...Field) { z.l.Error(msg, fields...) }
+
+func (z zapLogger) With(fields ...Field) Logger {
+	return zapLogger{l: z.l.With(fields...)}
+}
+
+func (z zapLogger) Named(name string) Logger {
+	return zapLogger{l: z.l.Named(name)}
+}
+
+func (z zapLogger) Ctx(ctx context.Context) Logger {
+	return zapLogger{l: ctxLogger(z.l, ctx)}
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// 依赖Logger接口的库
+func doLibraryWork(l Logger) {
+	l.Named("lib").With(String("component", "worker")).Info("library work")
+}
+
+func TestDefaultLogger(t *testing.T) {
+	filename := initTestLogger(t)
+	doLibraryWork(Default())
+	Default().Ctx(context.WithValue(context.Background(), "X-B3-TraceId", "trace-1")).Warn("ctx work")
+
+	lines := readLogLines(t, filename)
+	entry := findLogLine(lines, "library work")
+	if entry == nil || entry["logger"] != "lib" || entry["component"] != "worker" {
+		t.Fatalf("unexpected library entry %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "facade_test.go") {
+		t.Errorf("expected caller in facade_test.go, got %v", entry["caller"])
+	}
+	if entry = findLogLine(lines, "ctx work"); entry == nil || !strings.Contains(entry["caller"].(string), "facade_test.go") {
+		t.Errorf("unexpected ctx entry %v", entry)
+	}
+}
+
+func TestAsLogger(t *testing.T) {
+	var buf bytes.Buffer
+	l, err := NewLoggerWithEncoder(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), "debug")
+	if err != nil {
+		t.Fatal(err)
+	}
+	doLibraryWork(AsLogger(l))
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["logger"] != "lib" || entry["component"] != "worker" {
+		t.Errorf("unexpected entry %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "facade_test.go") {
+		t.Errorf("expected caller in facade_test.go, got %v", entry["caller"])
+	}
+}
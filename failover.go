@@ -0,0 +1,133 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	failoverRetryInterval = 10 * time.Second // 切换到备用输出后重试主输出的间隔
+	failoverMaxPending    = 1 << 20          // 主备输出都失败时最多缓存的字节数
+)
+
+// 主输出写入失败后切换到备用输出，并定期重试主输出，主备输出都失败时缓存日志，恢复后补写
+type failoverWriteSyncer struct {
+	mu        sync.Mutex
+	primary   zapcore.WriteSyncer
+	secondary zapcore.WriteSyncer
+
+	failed  bool
+	retryAt time.Time
+	pending [][]byte
+	size    int
+}
+
+func newFailoverWriteSyncer(primary, secondary zapcore.WriteSyncer) *failoverWriteSyncer {
+	return &failoverWriteSyncer{primary: primary, secondary: secondary}
+}
+
+func (w *failoverWriteSyncer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed && !time.Now().Before(w.retryAt) {
+		w.failed = false // 重试主输出
+	}
+	if !w.failed {
+		if w.flush(w.primary) == nil {
+			if _, err := w.primary.Write(p); err == nil {
+				return len(p), nil
+			}
+		}
+		w.failed = true
+		w.retryAt = time.Now().Add(failoverRetryInterval)
+	}
+
+	if w.flush(w.secondary) == nil {
+		if _, err := w.secondary.Write(p); err == nil {
+			return len(p), nil
+		}
+	}
+	w.buffer(p)
+	return len(p), nil
+}
+
+func (w *failoverWriteSyncer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.failed {
+		return w.secondary.Sync()
+	}
+	return w.primary.Sync()
+}
+
+// 补写缓存的日志到ws，写入失败时保留未写入的日志
+func (w *failoverWriteSyncer) flush(ws zapcore.WriteSyncer) error {
+	for len(w.pending) > 0 {
+		if _, err := ws.Write(w.pending[0]); err != nil {
+			return err
+		}
+		w.size -= len(w.pending[0])
+		w.pending = w.pending[1:]
+	}
+	w.pending = nil
+	return nil
+}
+
+// 缓存日志，p会被编码器复用，需要复制，超过上限时丢弃最早的日志
+func (w *failoverWriteSyncer) buffer(p []byte) {
+	w.pending = append(w.pending, append([]byte(nil), p...))
+	w.size += len(p)
+	for w.size > failoverMaxPending && len(w.pending) > 1 {
+		w.size -= len(w.pending[0])
+		w.pending = w.pending[1:]
+		countDropped(1)
+	}
+}
+
+// 初始化时打开失败的文件，写入时重新打开，由failoverWriteSyncer控制重试的间隔
+type reopenWriteSyncer struct {
+	mu      sync.Mutex
+	path    string
+	ws      zapcore.WriteSyncer
+	closeFn func()
+}
+
+func (w *reopenWriteSyncer) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.ws == nil {
+		ws, closeFn, err := zap.Open(w.path)
+		if err != nil {
+			return 0, err
+		}
+		w.ws, w.closeFn = ws, closeFn
+	}
+	return w.ws.Write(p)
+}
+
+func (w *reopenWriteSyncer) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.ws == nil {
+		return nil
+	}
+	return w.ws.Sync()
+}
+
+// 关闭已经重新打开的文件
+func (w *reopenWriteSyncer) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closeFn != nil {
+		w.closeFn()
+		w.ws, w.closeFn = nil, nil
+	}
+}
@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// 可以模拟写入失败的输出
+type flakyWriter struct {
+	bytes.Buffer
+	down bool
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	if w.down {
+		return 0, errors.New("sink unavailable")
+	}
+	return w.Buffer.Write(p)
+}
+
+func (w *flakyWriter) Sync() error { return nil }
+
+func TestFailoverWriteSyncer(t *testing.T) {
+	defer func(d time.Duration) { failoverRetryInterval = d }(failoverRetryInterval)
+	failoverRetryInterval = time.Hour
+
+	primary, secondary := &flakyWriter{}, &flakyWriter{}
+	ws := newFailoverWriteSyncer(primary, secondary)
+	write := func(line string) {
+		if _, err := ws.Write([]byte(line)); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+
+	write("line1\n")
+	primary.down = true
+	write("line2\n")
+	primary.down = false
+	write("line3\n") // 未到重试时间，仍然写入备用输出
+	if primary.String() != "line1\n" || secondary.String() != "line2\nline3\n" {
+		t.Fatalf("unexpected failover output, primary=%q, secondary=%q", primary.String(), secondary.String())
+	}
+
+	failoverRetryInterval = 0
+	secondary.down = true
+	ws.retryAt = time.Time{}
+	primary.down = true
+	write("line4\n") // 主备输出都失败，缓存日志
+	primary.down = false
+	write("line5\n")
+	if primary.String() != "line1\nline4\nline5\n" {
+		t.Errorf("expected buffered line replayed to primary, got %q", primary.String())
+	}
+}
+
+func TestWithFailover(t *testing.T) {
+	primary, secondary := &flakyWriter{down: true}, &flakyWriter{}
+	initTestLogger(t, WithFailover(primary, secondary))
+	Info("failover entry")
+
+	if !bytes.Contains(secondary.Bytes(), []byte("failover entry")) {
+		t.Errorf("expected entry in secondary, got %q", secondary.String())
+	}
+}
+
+func TestWithSaveFailover(t *testing.T) {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full not available")
+	}
+	dir := t.TempDir()
+	secondary := filepath.Join(dir, "secondary.log")
+
+	// 主文件写入失败(磁盘已满)
+	if err := Init(WithSaveFailover("/dev/full", secondary)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetLogger(t) })
+	Info("failover to secondary")
+	if findLogLine(readLogLines(t, secondary), "failover to secondary") == nil {
+		t.Error("expected entry in secondary file")
+	}
+
+	// 主文件打开失败，只写入第一个可用的文件
+	third := filepath.Join(dir, "third.log")
+	if err := Init(WithSaveFailover(filepath.Join(dir, "missing", "app.log"), secondary, third)); err != nil {
+		t.Fatal(err)
+	}
+	Info("skip unavailable primary")
+	if findLogLine(readLogLines(t, secondary), "skip unavailable primary") == nil {
+		t.Error("expected entry in secondary file")
+	}
+	if _, err := os.Stat(third); err != nil || findLogLine(readLogLines(t, third), "skip unavailable primary") != nil {
+		t.Error("expected entry not to be written to lower priority file")
+	}
+}
+
+func TestWithSaveFailoverReopen(t *testing.T) {
+	defer func(d time.Duration) { failoverRetryInterval = d }(failoverRetryInterval)
+	failoverRetryInterval = 0
+
+	dir := t.TempDir()
+	primary, secondary := filepath.Join(dir, "missing", "app.log"), filepath.Join(dir, "secondary.log")
+	if err := Init(WithSaveFailover(primary, secondary)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetLogger(t) })
+	Info("primary unavailable")
+
+	// 主文件的目录恢复后重新打开主文件
+	if err := os.Mkdir(filepath.Dir(primary), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	Info("primary reopened")
+	if findLogLine(readLogLines(t, secondary), "primary unavailable") == nil {
+		t.Error("expected entry in secondary file before primary is available")
+	}
+	if findLogLine(readLogLines(t, primary), "primary reopened") == nil {
+		t.Error("expected primary file to be reopened")
+	}
+
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	if isFileOpen(primary) {
+		t.Error("expected reopened primary file to be closed")
+	}
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"syscall"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 主输出写入失败(已关闭)后切换到备用输出，不再重试主输出
+type fallbackWriteSyncer struct {
+	primary   zapcore.WriteSyncer
+	secondary zapcore.WriteSyncer
+	failed    atomic.Bool
+}
+
+func newFallbackWriteSyncer(primary, secondary zapcore.WriteSyncer) *fallbackWriteSyncer {
+	return &fallbackWriteSyncer{primary: primary, secondary: secondary}
+}
+
+func (w *fallbackWriteSyncer) Write(p []byte) (int, error) {
+	if !w.failed.Load() {
+		n, err := w.primary.Write(p)
+		if err == nil || !isClosedError(err) {
+			return n, err
+		}
+		w.failed.Store(true)
+	}
+	return w.secondary.Write(p)
+}
+
+func (w *fallbackWriteSyncer) Sync() error {
+	if w.failed.Load() {
+		return w.secondary.Sync()
+	}
+	return w.primary.Sync()
+}
+
+// 判断是否为输出已关闭的错误
+func isClosedError(err error) bool {
+	return errors.Is(err, syscall.EBADF) || errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed)
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestFallbackWriteSyncer(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Close()
+	w.Close() // 模拟已关闭的stdout
+
+	secondary := &bytes.Buffer{}
+	ws := newFallbackWriteSyncer(w, zapcore.AddSync(secondary))
+	for _, line := range []string{"line1\n", "line2\n"} {
+		if _, err := ws.Write([]byte(line)); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+
+	if got := secondary.String(); got != "line1\nline2\n" {
+		t.Errorf("expected both lines in secondary, got %q", got)
+	}
+	if !ws.failed.Load() {
+		t.Error("expected primary to be marked as failed")
+	}
+}
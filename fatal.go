@@ -0,0 +1,50 @@
+package logger
+
+import (
+	"os"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	fatalMu       sync.RWMutex
+	fatalHookFunc func()
+	fatalExitCode = 1
+
+	exitFunc = os.Exit // 测试时替换
+)
+
+// SetFatalHook 设置Fatal日志输出后、进程退出前执行的函数，例如关闭数据库连接、上报指标
+func SetFatalHook(fn func()) {
+	fatalMu.Lock()
+	fatalHookFunc = fn
+	fatalMu.Unlock()
+}
+
+// SetFatalExitCode 设置Fatal日志输出后进程的退出码，默认为1
+func SetFatalExitCode(code int) {
+	fatalMu.Lock()
+	fatalExitCode = code
+	fatalMu.Unlock()
+}
+
+// Fatal日志写入后依次执行hook、刷新日志、退出进程
+type fatalHook struct{}
+
+func (fatalHook) OnWrite(*zapcore.CheckedEntry, []zapcore.Field) {
+	fatalMu.RLock()
+	fn, code := fatalHookFunc, fatalExitCode
+	fatalMu.RUnlock()
+
+	if fn != nil {
+		fn()
+	}
+	loggerMu.RLock()
+	logger := defaultLogger
+	loggerMu.RUnlock()
+	if logger != nil {
+		_ = logger.Sync()
+	}
+	exitFunc(code)
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"os"
+	"testing"
+)
+
+func TestFatalHook(t *testing.T) {
+	filename := initTestLogger(t)
+
+	var hookCalled bool
+	exitCode := -1
+	exitFunc = func(code int) { exitCode = code }
+	SetFatalHook(func() { hookCalled = true })
+	SetFatalExitCode(3)
+	defer func() {
+		exitFunc = os.Exit
+		SetFatalHook(nil)
+		SetFatalExitCode(1)
+	}()
+
+	Fatal("fatal with hook")
+
+	if !hookCalled {
+		t.Error("expected fatal hook to be called")
+	}
+	if exitCode != 3 {
+		t.Errorf("expected exit code 3, got %d", exitCode)
+	}
+	if findLogLine(readLogLines(t, filename), "fatal with hook") == nil {
+		t.Error("expected fatal entry to be written before exit")
+	}
+}
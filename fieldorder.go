@@ -0,0 +1,218 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// 基础字段名称
+const (
+	TimeField    = "time"
+	LevelField   = "level"
+	NameField    = "name"
+	CallerField  = "caller"
+	MessageField = "message"
+)
+
+var defaultFieldOrder = []string{TimeField, LevelField, NameField, CallerField, MessageField}
+
+// 按指定顺序输出基础字段(时间、级别、名称、调用位置、消息)的encoder，其他字段由内部encoder输出
+type orderedEncoder struct {
+	zapcore.Encoder
+	cfg     zapcore.EncoderConfig
+	order   []string
+	console bool
+}
+
+func newOrderedEncoder(encoding string, cfg zapcore.EncoderConfig, order []string) zapcore.Encoder {
+	// 未指定的基础字段按默认顺序排在后面
+	seen := make(map[string]bool)
+	var fullOrder []string
+	for _, key := range append(append([]string{}, order...), defaultFieldOrder...) {
+		if !seen[key] {
+			seen[key] = true
+			fullOrder = append(fullOrder, key)
+		}
+	}
+
+	inner := cfg
+	inner.TimeKey, inner.LevelKey, inner.NameKey, inner.CallerKey, inner.MessageKey = "", "", "", "", ""
+	return &orderedEncoder{
+		Encoder: newEncoder(encoding, inner),
+		cfg:     cfg,
+		order:   fullOrder,
+		console: encoding != "json",
+	}
+}
+
+func (e *orderedEncoder) Clone() zapcore.Encoder {
+	return &orderedEncoder{Encoder: e.Encoder.Clone(), cfg: e.cfg, order: e.order, console: e.console}
+}
+
+func (e *orderedEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	rest, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer rest.Free()
+
+	if e.console {
+		return e.encodeConsole(ent, rest.String()), nil
+	}
+	return e.encodeJSON(ent, rest.String())
+}
+
+// 按顺序获取基础字段的值
+func (e *orderedEncoder) baseFields(ent zapcore.Entry) (keys []string, values []interface{}) {
+	for _, field := range e.order {
+		var key string
+		arr := &primitiveCapture{}
+		switch field {
+		case TimeField:
+			if key = e.cfg.TimeKey; key != "" && e.cfg.EncodeTime != nil {
+				e.cfg.EncodeTime(ent.Time, arr)
+			}
+		case LevelField:
+			if key = e.cfg.LevelKey; key != "" && e.cfg.EncodeLevel != nil {
+				e.cfg.EncodeLevel(ent.Level, arr)
+			}
+		case NameField:
+			if key = e.cfg.NameKey; key != "" && ent.LoggerName != "" {
+				if e.cfg.EncodeName != nil {
+					e.cfg.EncodeName(ent.LoggerName, arr)
+				} else {
+					arr.AppendString(ent.LoggerName)
+				}
+			}
+		case CallerField:
+			if key = e.cfg.CallerKey; key != "" && ent.Caller.Defined && e.cfg.EncodeCaller != nil {
+				e.cfg.EncodeCaller(ent.Caller, arr)
+			}
+		case MessageField:
+			if key = e.cfg.MessageKey; key != "" {
+				arr.AppendString(ent.Message)
+			}
+		}
+		if key != "" && len(arr.values) > 0 {
+			keys = append(keys, key)
+			values = append(values, arr.values[0])
+		}
+	}
+	return keys, values
+}
+
+func (e *orderedEncoder) encodeJSON(ent zapcore.Entry, rest string) (*buffer.Buffer, error) {
+	head := zapcore.NewJSONEncoder(zapcore.EncoderConfig{LineEnding: e.cfg.LineEnding})
+	keys, values := e.baseFields(ent)
+	for i, key := range keys {
+		if v, ok := values[i].(string); ok {
+			head.AddString(key, v)
+		} else if err := head.AddReflected(key, values[i]); err != nil {
+			return nil, err
+		}
+	}
+	buf, err := head.EncodeEntry(zapcore.Entry{}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// 合并基础字段和其他字段：{"level":...} + {"key":...}
+	line := strings.TrimRight(buf.String(), "\r\n")
+	buf.Reset()
+	rest = strings.TrimPrefix(rest, "{")
+	switch {
+	case len(keys) == 0:
+		buf.AppendString("{")
+	case strings.HasPrefix(rest, "}"):
+		buf.AppendString(strings.TrimSuffix(line, "}"))
+	default:
+		buf.AppendString(strings.TrimSuffix(line, "}"))
+		buf.AppendString(",")
+	}
+	buf.AppendString(rest)
+	return buf, nil
+}
+
+func (e *orderedEncoder) encodeConsole(ent zapcore.Entry, rest string) *buffer.Buffer {
+	sep := e.cfg.ConsoleSeparator
+	if sep == "" {
+		sep = "\t"
+	}
+
+	buf := bufferPool.Get()
+	_, values := e.baseFields(ent)
+	for i, v := range values {
+		if i > 0 {
+			buf.AppendString(sep)
+		}
+		buf.AppendString(toString(v))
+	}
+	if strings.TrimRight(rest, "\r\n") != "" && len(values) > 0 {
+		buf.AppendString(sep)
+	}
+	buf.AppendString(rest)
+	return buf
+}
+
+var bufferPool = buffer.NewPool()
+
+// 记录编码函数输出的基础类型值
+type primitiveCapture struct {
+	values []interface{}
+}
+
+func (p *primitiveCapture) AppendBool(v bool)             { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendByteString(v []byte)     { p.values = append(p.values, string(v)) }
+func (p *primitiveCapture) AppendComplex128(v complex128) { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendComplex64(v complex64)   { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendFloat64(v float64)       { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendFloat32(v float32)       { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendInt(v int)               { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendInt64(v int64)           { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendInt32(v int32)           { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendInt16(v int16)           { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendInt8(v int8)             { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendString(v string)         { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendUint(v uint)             { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendUint64(v uint64)         { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendUint32(v uint32)         { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendUint16(v uint16)         { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendUint8(v uint8)           { p.values = append(p.values, v) }
+func (p *primitiveCapture) AppendUintptr(v uintptr)       { p.values = append(p.values, v) }
+
+func toString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprint(v)
+}
+
+// 按字段名排序输出字段的core，With添加的字段不传给内部的core，输出时和日志的字段一起排序
+type sortedFieldsCore struct {
+	zapcore.Core
+	fields []Field
+}
+
+func (c *sortedFieldsCore) With(fields []Field) zapcore.Core {
+	return &sortedFieldsCore{Core: c.Core, fields: append(c.fields[:len(c.fields):len(c.fields)], fields...)}
+}
+
+func (c *sortedFieldsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return (&interceptCore{Core: c.Core, fn: c.sort}).Check(ent, ce)
+}
+
+func (c *sortedFieldsCore) Write(ent zapcore.Entry, fields []Field) error {
+	ent, fields, _ = c.sort(ent, fields)
+	return c.Core.Write(ent, fields)
+}
+
+func (c *sortedFieldsCore) sort(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+	all := make([]Field, 0, len(c.fields)+len(fields))
+	all = append(append(all, c.fields...), fields...)
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Key < all[j].Key })
+	return ent, all, true
+}
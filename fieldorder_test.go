@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestOrderedEncoder(t *testing.T) {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = timeFormatter
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC), Message: "hello"}
+
+	enc := newOrderedEncoder("json", cfg, []string{LevelField, TimeField, MessageField})
+	buf, err := enc.EncodeEntry(ent, []zapcore.Field{zap.String("k", "v")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"level":"info","ts":"2024-06-01 08:00:00.000","msg":"hello","k":"v"}` + "\n"; buf.String() != want {
+		t.Errorf("expected %s, got %s", want, buf.String())
+	}
+
+	enc = newOrderedEncoder("console", cfg, []string{MessageField, LevelField})
+	buf, err = enc.EncodeEntry(ent, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "hello\tinfo\t2024-06-01 08:00:00.000\n"; buf.String() != want {
+		t.Errorf("expected %q, got %q", want, buf.String())
+	}
+
+	enc = newOrderedEncoder("console", cfg, []string{LevelField})
+	buf, err = enc.EncodeEntry(ent, []zapcore.Field{zap.String("k", "v")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "info\t") || !strings.HasSuffix(buf.String(), "hello\t{\"k\": \"v\"}\n") {
+		t.Errorf("unexpected console output %q", buf.String())
+	}
+}
+
+func TestWithSortedFields(t *testing.T) {
+	if err := Init(WithEncoding("console"), WithColor(false), WithSortedFields()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetLogger(t) })
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+
+	WithFields(String("m", "1"), String("z", "2")).Info("sorted fields", String("b", "3"), String("a", "4"))
+	if !strings.Contains(buf.String(), `{"a": "4", "b": "3", "m": "1", "z": "2"}`) {
+		t.Errorf("expected sorted fields, got %q", buf.String())
+	}
+}
@@ -0,0 +1,60 @@
+package logger
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// FieldSet 可以重复使用的一组字段，WithFieldSet在字段和全局logger都不变时复用派生的logger，
+// 避免每个请求都用相同的字段调用WithFields新建logger，并发安全
+//
+//	eg:
+//	var svcFields = NewFieldSet(String("service", "order"), String("region", "cn"))
+//	WithFieldSet(svcFields).Info("create order")
+type FieldSet struct {
+	mu     sync.Mutex
+	fields []Field
+	cached atomic.Pointer[fieldSetLogger]
+}
+
+// 缓存的派生logger，base为派生时的全局logger
+type fieldSetLogger struct {
+	base   *zap.Logger
+	logger *zap.Logger
+}
+
+// NewFieldSet 新建字段集合
+func NewFieldSet(fields ...Field) *FieldSet {
+	return &FieldSet{fields: append([]Field(nil), fields...)}
+}
+
+// Fields 返回字段集合的副本
+func (s *FieldSet) Fields() []Field {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Field(nil), s.fields...)
+}
+
+// Set 替换字段集合中的字段，之后WithFieldSet重新派生logger
+func (s *FieldSet) Set(fields ...Field) {
+	s.mu.Lock()
+	s.fields = append([]Field(nil), fields...)
+	s.cached.Store(nil)
+	s.mu.Unlock()
+}
+
+// WithFieldSet 返回携带字段集合s的logger，字段和全局logger都不变时返回缓存的logger，不再分配
+func WithFieldSet(s *FieldSet) *zap.Logger {
+	base := loadLogger()
+	if c := s.cached.Load(); c != nil && c.base == base {
+		return c.logger
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	logger := base.With(s.fields...)
+	s.cached.Store(&fieldSetLogger{base: base, logger: logger})
+	return logger
+}
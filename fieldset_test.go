@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"io"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithFieldSet(t *testing.T) {
+	filename := initTestLogger(t)
+	set := NewFieldSet(String("service", "order"), Int("shard", 1))
+
+	l := WithFieldSet(set)
+	if WithFieldSet(set) != l {
+		t.Error("expected cached logger to be reused")
+	}
+	l.Info("field set")
+	entry := findLogLine(readLogLines(t, filename), "field set")
+	if entry["service"] != "order" || entry["shard"] != float64(1) {
+		t.Errorf("expected field set fields, got %v", entry)
+	}
+
+	set.Set(String("service", "payment"))
+	WithFieldSet(set).Info("field set changed")
+	entry = findLogLine(readLogLines(t, filename), "field set changed")
+	if entry["service"] != "payment" || entry["shard"] != nil {
+		t.Errorf("expected updated fields, got %v", entry)
+	}
+
+	// 重新初始化后重新派生
+	filename = initTestLogger(t)
+	WithFieldSet(set).Info("after reinit")
+	if entry = findLogLine(readLogLines(t, filename), "after reinit"); entry["service"] != "payment" {
+		t.Errorf("expected entry in new logger output, got %v", entry)
+	}
+}
+
+func BenchmarkWithFieldSet(b *testing.B) {
+	if err := Init(WithSave(filepath.Join(b.TempDir(), "out.log"))); err != nil {
+		b.Fatal(err)
+	}
+	defer resetLogger(b)
+	SetOutput(io.Discard)
+	fields := []Field{String("service", "order"), String("region", "cn"), Int("shard", 1)}
+
+	b.Run("WithFields", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			WithFields(fields...).Info("request")
+		}
+	})
+	b.Run("WithFieldSet", func(b *testing.B) {
+		set := NewFieldSet(fields...)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			WithFieldSet(set).Info("request")
+		}
+	})
+}
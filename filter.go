@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Entry 日志条目类型
+type Entry = zapcore.Entry
+
+// AddFilter 添加过滤函数，返回false的日志不输出，例如过滤健康检查的日志，
+// 多次调用时所有过滤函数都返回true才输出，重新初始化logger后需要重新添加
+func AddFilter(fn func(Entry, []Field) bool) {
+	updateLogger(func(logger *zap.Logger) *zap.Logger {
+		return logger.WithOptions(wrapInner(func(core zapcore.Core) zapcore.Core {
+			return &interceptCore{Core: core, fn: func(ent Entry, fields []Field) (Entry, []Field, bool) {
+				return ent, fields, fn(ent, fields)
+			}}
+		}))
+	})
+}
+
+// RequireFields 要求level及以上级别的日志必须携带keys字段(包括With添加的字段)，
+// 缺少字段时在日志中添加missing_fields字段说明缺少的字段，用于在测试中发现不规范的日志，重新初始化logger后需要重新设置
+//
+//	eg: RequireFields(zapcore.ErrorLevel, "trace_id", "error")
+func RequireFields(level zapcore.Level, keys ...string) {
+	updateLogger(func(logger *zap.Logger) *zap.Logger {
+		return logger.WithOptions(wrapInner(func(core zapcore.Core) zapcore.Core {
+			return &requireFieldsCore{Core: core, level: level, keys: keys}
+		}))
+	})
+}
+
+// 检查必须字段的core，present为With添加的字段
+type requireFieldsCore struct {
+	zapcore.Core
+	level   zapcore.Level
+	keys    []string
+	present map[string]struct{}
+}
+
+func (c *requireFieldsCore) With(fields []Field) zapcore.Core {
+	present := make(map[string]struct{}, len(c.present)+len(fields))
+	for k := range c.present {
+		present[k] = struct{}{}
+	}
+	for _, f := range fields {
+		present[f.Key] = struct{}{}
+	}
+	return &requireFieldsCore{Core: c.Core.With(fields), level: c.level, keys: c.keys, present: present}
+}
+
+func (c *requireFieldsCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if ent.Level < c.level {
+		return c.Core.Check(ent, ce)
+	}
+	return (&interceptCore{Core: c.Core, fn: c.check}).Check(ent, ce)
+}
+
+func (c *requireFieldsCore) check(ent Entry, fields []Field) (Entry, []Field, bool) {
+	var missing []string
+	for _, key := range c.keys {
+		if _, ok := c.present[key]; ok {
+			continue
+		}
+		found := false
+		for _, f := range fields {
+			if f.Key == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, key)
+		}
+	}
+
+	if len(missing) > 0 { // 添加到原来的日志中，不额外输出日志，避免绕过全局日志级别
+		fields = append(fields[:len(fields):len(fields)], Strings("missing_fields", missing))
+	}
+	return ent, fields, true
+}
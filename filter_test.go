@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestAddFilter(t *testing.T) {
+	filename := initTestLogger(t)
+	AddFilter(func(_ Entry, fields []Field) bool {
+		for _, field := range fields {
+			if field.Key == "path" && field.String == "/healthz" {
+				return false
+			}
+		}
+		return true
+	})
+	AddFilter(func(ent Entry, _ []Field) bool {
+		return ent.Message != "noise"
+	})
+
+	Info("request", String("path", "/healthz"))
+	Info("request", String("path", "/users"))
+	Info("noise")
+
+	var paths []interface{}
+	for _, line := range readLogLines(t, filename) {
+		if line["msg"] == "noise" {
+			t.Error("expected noise entry to be filtered")
+		}
+		if line["msg"] == "request" {
+			paths = append(paths, line["path"])
+		}
+	}
+	if len(paths) != 1 || paths[0] != "/users" {
+		t.Errorf("expected only /users entry, got %v", paths)
+	}
+}
+
+func TestAddFilterMessageContent(t *testing.T) {
+	filename := initTestLogger(t)
+	AddFilter(func(ent Entry, _ []Field) bool {
+		return !strings.Contains(ent.Message, "TLS handshake error")
+	})
+
+	// 通过WithFields派生的logger同样被过滤
+	l := WithFields(String("component", "http"))
+	l.Info("http: TLS handshake error from 10.0.0.1:5555: EOF")
+	l.Info("server started")
+
+	var msgs []interface{}
+	for _, line := range readLogLines(t, filename) {
+		if line["component"] == "http" {
+			msgs = append(msgs, line["msg"])
+		}
+	}
+	if len(msgs) != 1 || msgs[0] != "server started" {
+		t.Errorf("expected only server started entry, got %v", msgs)
+	}
+}
+
+func TestRequireFields(t *testing.T) {
+	filename := initTestLogger(t, WithLogLevel("error"))
+	RequireFields(zapcore.ErrorLevel, "trace_id", "error")
+
+	Error("missing fields", String("trace_id", "t-1"))
+	WithFields(String("trace_id", "t-2")).Error("with fields", Err(errors.New("failed")))
+	Warn("below level")
+
+	lines := readLogLines(t, filename)
+	entry := findLogLine(lines, "missing fields")
+	missing, _ := entry["missing_fields"].([]interface{})
+	if len(missing) != 1 || missing[0] != "error" {
+		t.Errorf("expected missing_fields [error], got %v", entry)
+	}
+	if entry := findLogLine(lines, "with fields"); entry == nil || entry["missing_fields"] != nil {
+		t.Errorf("expected no missing_fields, got %v", entry)
+	}
+	// 不额外输出低于全局日志级别的日志
+	for _, line := range lines {
+		if line["level"] == "warn" {
+			t.Errorf("unexpected warn entry %v", line)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+)
+
+// token对应的字段，value为[]Field
+var goroutineFields sync.Map
+
+// SetGoroutineFields 设置token对应的字段，使用同一个token调用DebugG、InfoG、WarnG、ErrorG时都会携带这些字段，
+// 用于不方便传递ctx的代码，例如worker开始处理任务时设置job_id，处理结束后调用ClearGoroutineFields清除
+//
+//	eg: SetGoroutineFields(jobID, String("job_id", jobID))
+//	    defer ClearGoroutineFields(jobID)
+//	    InfoG(jobID, "job started")
+func SetGoroutineFields(token string, fields ...Field) {
+	goroutineFields.Store(token, append([]Field(nil), fields...))
+}
+
+// ClearGoroutineFields 清除token对应的字段
+func ClearGoroutineFields(token string) {
+	goroutineFields.Delete(token)
+}
+
+// 合并token对应的字段和fields
+func withGoroutineFields(token string, fields []Field) []Field {
+	v, ok := goroutineFields.Load(token)
+	if !ok {
+		return fields
+	}
+	return append(append([]Field(nil), v.([]Field)...), fields...)
+}
+
+// DebugG debug级别信息，携带token对应的字段
+func DebugG(token string, msg string, fields ...Field) {
+	getLogger().Debug(msg, withGoroutineFields(token, fields)...)
+}
+
+// InfoG info级别信息，携带token对应的字段
+func InfoG(token string, msg string, fields ...Field) {
+	getLogger().Info(msg, withGoroutineFields(token, fields)...)
+}
+
+// WarnG warn级别信息，携带token对应的字段
+func WarnG(token string, msg string, fields ...Field) {
+	getLogger().Warn(msg, withGoroutineFields(token, fields)...)
+}
+
+// ErrorG error级别信息，携带token对应的字段
+func ErrorG(token string, msg string, fields ...Field) {
+	getLogger().Error(msg, withGoroutineFields(token, fields)...)
+}
+
+// GoID 当前goroutine的id字段goid，用于排查并发问题，
+// id从runtime.Stack的输出中解析，每次调用耗时为微秒级并有一次内存分配(见BenchmarkGoID)，不要在高频日志中每次调用。
+// Go没有goroutine本地存储，无法在包内按goroutine缓存，同一个goroutine中可以保存返回的字段重复使用，
+// 或者用WithFields(GoID())创建携带goid的logger，只解析一次
+//
+//	eg: goid := GoID()
+//	    for _, job := range jobs {
+//	        Info("process job", goid, String("job_id", job.ID))
+//	    }
+func GoID() Field {
+	return Int64("goid", goid())
+}
+
+func goid() int64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseInt(string(b), 10, 64)
+	return id
+}
@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestGoroutineFields(t *testing.T) {
+	filename := initTestLogger(t)
+
+	var wg sync.WaitGroup
+	for _, jobID := range []string{"job-1", "job-2"} {
+		wg.Add(1)
+		go func(jobID string) {
+			defer wg.Done()
+			SetGoroutineFields(jobID, String("job_id", jobID))
+			defer ClearGoroutineFields(jobID)
+			InfoG(jobID, "job started "+jobID, Int("step", 1))
+		}(jobID)
+	}
+	wg.Wait()
+	InfoG("job-1", "after clear")
+	InfoG("unknown", "unknown token")
+
+	lines := readLogLines(t, filename)
+	for _, jobID := range []string{"job-1", "job-2"} {
+		entry := findLogLine(lines, "job started "+jobID)
+		if entry["job_id"] != jobID || entry["step"] != float64(1) {
+			t.Errorf("expected job_id %s, got %v", jobID, entry)
+		}
+		if caller, _ := entry["caller"].(string); !strings.Contains(caller, "goroutine_test.go") {
+			t.Errorf("expected caller in goroutine_test.go, got %v", entry["caller"])
+		}
+	}
+	for _, msg := range []string{"after clear", "unknown token"} {
+		if entry := findLogLine(lines, msg); entry["job_id"] != nil {
+			t.Errorf("unexpected job_id in %q: %v", msg, entry)
+		}
+	}
+}
+
+func TestGoID(t *testing.T) {
+	id := FieldsToMap(GoID())["goid"].(int64)
+	if id <= 0 {
+		t.Fatalf("expected positive goroutine id, got %d", id)
+	}
+
+	other := make(chan int64)
+	go func() { other <- FieldsToMap(GoID())["goid"].(int64) }()
+	if otherID := <-other; otherID <= 0 || otherID == id {
+		t.Errorf("expected a different positive id in another goroutine, got %d and %d", id, otherID)
+	}
+}
+
+func BenchmarkGoID(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = GoID()
+	}
+}
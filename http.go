@@ -0,0 +1,154 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LevelForStatus 根据http状态码返回日志级别，2xx/3xx: info，4xx: warn，5xx: error
+func LevelForStatus(code int) string {
+	switch {
+	case code >= 500:
+		return "error"
+	case code >= 400:
+		return "warn"
+	default:
+		return "info"
+	}
+}
+
+// LogStatus 根据http状态码对应的日志级别输出日志，携带ctx中的链路信息
+func LogStatus(ctx context.Context, code int, msg string, fields ...Field) {
+	logStatus(Ctx(ctx).WithOptions(zap.AddCallerSkip(1)), code, msg, fields...)
+}
+
+// logger需要跳过调用logStatus的一层
+func logStatus(logger *zap.Logger, code int, msg string, fields ...Field) {
+	switch LevelForStatus(code) {
+	case "error":
+		logger.Error(msg, fields...)
+	case "warn":
+		logger.Warn(msg, fields...)
+	default:
+		logger.Info(msg, fields...)
+	}
+}
+
+// HTTPError 按状态码对应的级别输出请求的错误日志，并返回json格式的错误信息{"code": status, "msg": msg}，
+// 日志携带请求context中的链路信息，err只输出到日志不返回给客户端
+func HTTPError(w http.ResponseWriter, r *http.Request, status int, msg string, err error) {
+	fields := []Field{String("method", r.Method), String("path", r.URL.Path), Int("status", status)}
+	if err != nil {
+		fields = append(fields, Err(err))
+	}
+	logStatus(Ctx(r.Context()).WithOptions(zap.AddCallerSkip(1)), status, msg, fields...)
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}{status, msg})
+}
+
+// PanicRecoveryMiddleware 恢复http handler中的panic，输出包含请求方法、路径、panic值和堆栈的error级别日志，
+// 并返回500，日志携带请求context中的链路信息，调用位置为引发panic的位置
+func PanicRecoveryMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			if rec == http.ErrAbortHandler { // 由http.Server处理
+				panic(rec)
+			}
+
+			// 堆栈已在stack字段中，不再重复输出error级别的stacktrace
+			noStacktrace := zap.AddStacktrace(zap.LevelEnablerFunc(func(zapcore.Level) bool { return false }))
+			ctxLogger(loadLogger(), r.Context()).WithOptions(noStacktrace, zap.AddCallerSkip(panicCallerSkip())).Error("http handler panic",
+				String("method", r.Method),
+				String("path", r.URL.Path),
+				Any("panic", rec),
+				String("stack", string(debug.Stack())),
+			)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// 在recover的defer函数中调用，返回引发panic的位置相对于defer函数的层数，
+// 跳过runtime.gopanic和runtime内部引发panic的函数(例如空指针时的runtime.sigpanic、写入nil map时的internal/runtime/maps)，找不到时返回0
+func panicCallerSkip() int {
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs) // 跳过runtime.Callers、panicCallerSkip，从defer函数开始
+	frames := runtime.CallersFrames(pcs[:n])
+	panicking := false
+	for skip := 0; ; skip++ {
+		frame, more := frames.Next()
+		if panicking && !strings.HasPrefix(frame.Function, "runtime.") && !strings.HasPrefix(frame.Function, "internal/runtime/") {
+			return skip
+		}
+		if frame.Function == "runtime.gopanic" {
+			panicking = true
+		}
+		if !more {
+			return 0
+		}
+	}
+}
+
+// 需要打码的请求头，key为规范化的请求头名称
+var sensitiveHeaders atomic.Pointer[map[string]struct{}]
+
+func init() {
+	SetSensitiveHeaders("Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization")
+}
+
+// SetSensitiveHeaders 设置Header输出时需要打码的请求头名称，不区分大小写，
+// 默认为Authorization, Cookie, Set-Cookie, Proxy-Authorization
+func SetSensitiveHeaders(names ...string) {
+	m := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		m[http.CanonicalHeaderKey(name)] = struct{}{}
+	}
+	sensitiveHeaders.Store(&m)
+}
+
+// Header http.Header类型，按名称排序输出为对象，多个值用", "连接，敏感的请求头的值输出为"***"
+//
+//	eg: Debug("request", Header("headers", r.Header))
+func Header(key string, h http.Header) Field {
+	return zap.Object(key, headerObject(h))
+}
+
+type headerObject http.Header
+
+func (h headerObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sensitive := *sensitiveHeaders.Load()
+	for _, name := range names {
+		if _, ok := sensitive[http.CanonicalHeaderKey(name)]; ok {
+			enc.AddString(name, "***")
+			continue
+		}
+		enc.AddString(name, strings.Join(h[name], ", "))
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLevelForStatus(t *testing.T) {
+	tests := []struct {
+		code int
+		want string
+	}{
+		{200, "info"},
+		{399, "info"},
+		{400, "warn"},
+		{499, "warn"},
+		{500, "error"},
+	}
+	for _, tt := range tests {
+		if got := LevelForStatus(tt.code); got != tt.want {
+			t.Errorf("LevelForStatus(%d) = %s, want %s", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestLogStatus(t *testing.T) {
+	filename := initTestLogger(t)
+	for _, code := range []int{200, 404, 503} {
+		LogStatus(context.Background(), code, fmt.Sprintf("status %d", code))
+	}
+
+	lines := readLogLines(t, filename)
+	for code, want := range map[int]string{200: "info", 404: "warn", 503: "error"} {
+		entry := findLogLine(lines, fmt.Sprintf("status %d", code))
+		if entry == nil || entry["level"] != want {
+			t.Errorf("status %d: expected level %s, got %v", code, want, entry)
+		}
+	}
+}
+
+func TestHTTPError(t *testing.T) {
+	filename := initTestLogger(t)
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	HTTPError(w, r, http.StatusNotFound, "user not found", errors.New("record not found"))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+	if body := strings.TrimSpace(w.Body.String()); body != `{"code":404,"msg":"user not found"}` {
+		t.Errorf("unexpected body %s", body)
+	}
+
+	entry := findLogLine(readLogLines(t, filename), "user not found")
+	if entry == nil || entry["level"] != "warn" || entry["path"] != "/users/1" || entry["error"] != "record not found" {
+		t.Errorf("unexpected log entry %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "http_test.go") {
+		t.Errorf("expected caller in http_test.go, got %v", entry["caller"])
+	}
+}
+
+func TestPanicRecoveryMiddleware(t *testing.T) {
+	filename := initTestLogger(t)
+
+	handler := PanicRecoveryMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("boom")
+	}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/orders", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+
+	var entries []map[string]interface{}
+	for _, line := range readLogLines(t, filename) {
+		if line["level"] == "error" {
+			entries = append(entries, line)
+		}
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 error entry, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry["method"] != "POST" || entry["path"] != "/orders" || entry["panic"] != "boom" {
+		t.Errorf("unexpected log entry %v", entry)
+	}
+	if stack, _ := entry["stack"].(string); !strings.Contains(stack, "goroutine") {
+		t.Errorf("expected panic stack, got %v", entry["stack"])
+	}
+	if _, ok := entry["stacktrace"]; ok {
+		t.Errorf("expected the stack to be logged only once, got %v", entry)
+	}
+	// 调用位置为handler中引发panic的位置，而不是runtime/panic.go
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "http_test.go") {
+		t.Errorf("expected caller at the panic in http_test.go, got %v", entry["caller"])
+	}
+
+	// runtime引发的panic同样定位到handler
+	handler = PanicRecoveryMiddleware(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		var m map[string]int
+		m["a"] = 1
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nil", nil))
+	for _, line := range readLogLines(t, filename) {
+		if line["path"] == "/nil" {
+			entry = line
+		}
+	}
+	if caller, _ := entry["caller"].(string); entry["path"] != "/nil" || !strings.Contains(caller, "http_test.go") {
+		t.Errorf("expected caller at the runtime panic in http_test.go, got %v", entry)
+	}
+}
+
+func TestHeader(t *testing.T) {
+	filename := initTestLogger(t)
+	h := http.Header{}
+	h.Set("Authorization", "Bearer secret")
+	h.Set("Cookie", "session=secret")
+	h.Set("Content-Type", "application/json")
+	h.Add("Accept", "text/html")
+	h.Add("Accept", "application/json")
+	Info("request headers", Header("headers", h))
+
+	SetSensitiveHeaders("x-api-key")
+	defer SetSensitiveHeaders("Authorization", "Cookie", "Set-Cookie", "Proxy-Authorization")
+	Info("custom sensitive headers", Header("headers", http.Header{"X-Api-Key": {"secret"}, "Cookie": {"a=b"}}))
+
+	lines := readLogLines(t, filename)
+	headers, _ := findLogLine(lines, "request headers")["headers"].(map[string]interface{})
+	if headers["Authorization"] != "***" || headers["Cookie"] != "***" {
+		t.Errorf("expected sensitive headers redacted, got %v", headers)
+	}
+	if headers["Content-Type"] != "application/json" || headers["Accept"] != "text/html, application/json" {
+		t.Errorf("expected header values, got %v", headers)
+	}
+	headers, _ = findLogLine(lines, "custom sensitive headers")["headers"].(map[string]interface{})
+	if headers["X-Api-Key"] != "***" || headers["Cookie"] != "a=b" {
+		t.Errorf("expected custom sensitive headers, got %v", headers)
+	}
+}
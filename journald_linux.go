@@ -0,0 +1,144 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// journald的socket路径，测试时可修改
+var journaldSocket = "/run/systemd/journal/socket"
+
+// 通过journald原生协议写入日志的core，每条日志发送一个数据报
+type journaldCore struct {
+	zapcore.LevelEnabler
+	conn   *net.UnixConn
+	fields []Field
+}
+
+// journald socket不可用时退化为输出到stderr
+func newJournaldCore(encoder zapcore.Encoder, enab zapcore.LevelEnabler) (zapcore.Core, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journaldSocket, Net: "unixgram"})
+	if err != nil {
+		return zapcore.NewCore(encoder, zapcore.Lock(os.Stderr), enab), nil
+	}
+	return &journaldCore{LevelEnabler: enab, conn: conn}, nil
+}
+
+func (c *journaldCore) With(fields []Field) zapcore.Core {
+	clone := *c
+	clone.fields = append(append([]Field{}, c.fields...), fields...)
+	return &clone
+}
+
+func (c *journaldCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *journaldCore) Write(ent zapcore.Entry, fields []Field) error {
+	_, err := c.conn.Write(journaldPayload(ent, append(append([]Field{}, c.fields...), fields...)))
+	return err
+}
+
+func (c *journaldCore) Sync() error {
+	return nil
+}
+
+// 关闭journald连接，With派生的core共用同一个连接
+func (c *journaldCore) Close() error {
+	return c.conn.Close()
+}
+
+// 组装journald原生协议的数据，日志字段的key转为大写作为journald字段
+func journaldPayload(ent zapcore.Entry, fields []Field) []byte {
+	buf := &bytes.Buffer{}
+	journaldAppend(buf, "MESSAGE", ent.Message)
+	journaldAppend(buf, "PRIORITY", strconv.Itoa(severity(ent.Level)))
+	if ent.LoggerName != "" {
+		journaldAppend(buf, "SYSLOG_IDENTIFIER", ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		journaldAppend(buf, "CODE_FILE", ent.Caller.File)
+		journaldAppend(buf, "CODE_LINE", strconv.Itoa(ent.Caller.Line))
+		if ent.Caller.Function != "" {
+			journaldAppend(buf, "CODE_FUNC", ent.Caller.Function)
+		}
+	}
+	if ent.Stack != "" {
+		journaldAppend(buf, "STACKTRACE", ent.Stack)
+	}
+
+	m := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		field.AddTo(m)
+	}
+	keys := make([]string, 0, len(m.Fields))
+	for key := range m.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		journaldAppend(buf, journaldKey(key), journaldValue(m.Fields[key]))
+	}
+	return buf.Bytes()
+}
+
+// 值不包含换行时为KEY=value，否则使用KEY\n<64位小端长度><value>格式
+func journaldAppend(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journald字段名只能包含大写字母、数字和下划线，不能以下划线或数字开头，最长64个字符
+func journaldKey(key string) string {
+	b := []byte(strings.ToUpper(key))
+	for i, c := range b {
+		if !(c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			b[i] = '_'
+		}
+	}
+	key = strings.TrimLeft(string(b), "_")
+	if key == "" || key[0] >= '0' && key[0] <= '9' {
+		key = "F_" + key
+	}
+	if len(key) > 64 {
+		key = key[:64]
+	}
+	return key
+}
+
+func journaldValue(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case fmt.Stringer:
+		return val.String()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,90 @@
+//go:build linux
+
+package logger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithJournald(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not available: %v", err)
+	}
+	defer conn.Close()
+
+	old := journaldSocket
+	journaldSocket = socket
+	defer func() { journaldSocket = old }()
+
+	initTestLogger(t, WithJournald())
+	Error("charge failed", Int("user_id", 42), String("order.id", "o-1"))
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 4096)
+	var payload string
+	for !strings.Contains(payload, "charge failed") {
+		n, err := conn.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload = string(buf[:n])
+	}
+	for _, want := range []string{"MESSAGE=charge failed\n", "PRIORITY=3\n", "USER_ID=42\n", "ORDER_ID=o-1\n", "CODE_LINE="} {
+		if !strings.Contains(payload, want) {
+			t.Errorf("payload missing %q: %q", want, payload)
+		}
+	}
+
+	o := &options{}
+	core, err := newJournaldCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{}), zapcore.DebugLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	o.closeCore(core)
+	o.close()
+	if _, err := core.(*journaldCore).conn.Write([]byte("MESSAGE=closed\n")); err == nil {
+		t.Error("expected journald connection to be closed")
+	}
+}
+
+func TestWithJournaldFallback(t *testing.T) {
+	old := journaldSocket
+	journaldSocket = filepath.Join(t.TempDir(), "missing.sock")
+	defer func() { journaldSocket = old }()
+
+	core, err := newJournaldCore(zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"}), zapcore.DebugLevel)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := core.(*journaldCore); ok {
+		t.Error("expected stderr core when socket is unavailable")
+	}
+}
+
+func TestJournaldPayload(t *testing.T) {
+	ent := zapcore.Entry{Level: zapcore.WarnLevel, Message: "line1\nline2"}
+	payload := journaldPayload(ent, []Field{String("_trusted", "x"), Int("1st", 1)})
+
+	var want bytes.Buffer
+	want.WriteString("MESSAGE\n")
+	_ = binary.Write(&want, binary.LittleEndian, uint64(len(ent.Message)))
+	want.WriteString(ent.Message + "\n")
+	if !bytes.HasPrefix(payload, want.Bytes()) {
+		t.Errorf("multi-line message not length encoded: %q", payload)
+	}
+	for _, s := range []string{"PRIORITY=4\n", "TRUSTED=x\n", "F_1ST=1\n"} {
+		if !bytes.Contains(payload, []byte(s)) {
+			t.Errorf("payload missing %q: %q", s, payload)
+		}
+	}
+}
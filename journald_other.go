@@ -0,0 +1,13 @@
+//go:build !linux
+
+package logger
+
+import (
+	"errors"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func newJournaldCore(zapcore.Encoder, zapcore.LevelEnabler) (zapcore.Core, error) {
+	return nil, errors.New("journald is only supported on linux")
+}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// WithLevel 临时修改全局logger的日志级别，返回恢复原日志级别的函数，level为DEBUG, INFO, WARN, ERROR，
+// 修改对所有goroutine同时生效，不只是当前代码块，level无效时不修改，
+// 恢复前重新初始化了logger或者日志级别已被修改时不恢复，避免覆盖更新的设置
+//
+//	eg: defer WithLevel("debug")()
+func WithLevel(level string) (restore func()) {
+	lvl, err := zapcore.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		return func() {}
+	}
+	atomicLevel, ok := levelOf(loadLogger())
+	if !ok {
+		return func() {}
+	}
+
+	old := atomicLevel.Level()
+	atomicLevel.SetLevel(lvl)
+	loggerMu.Lock()
+	effectiveConfig.Level = lvl.String()
+	loggerMu.Unlock()
+
+	return func() {
+		loggerMu.Lock()
+		defer loggerMu.Unlock()
+		if active, ok := levelOf(defaultLogger); !ok || active != atomicLevel || atomicLevel.Level() != lvl {
+			return
+		}
+		atomicLevel.SetLevel(old)
+		effectiveConfig.Level = old.String()
+	}
+}
+
+// logger的日志级别，由rootCore判断
+func levelOf(logger *zap.Logger) (zap.AtomicLevel, bool) {
+	if logger == nil {
+		return zap.AtomicLevel{}, false
+	}
+	rc, ok := logger.Core().(*rootCore)
+	if !ok {
+		return zap.AtomicLevel{}, false
+	}
+	atomicLevel, ok := rc.enab.(zap.AtomicLevel)
+	return atomicLevel, ok
+}
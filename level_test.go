@@ -0,0 +1,51 @@
+package logger
+
+import "testing"
+
+func TestWithLevel(t *testing.T) {
+	filename := initTestLogger(t, WithLogLevel("warn"))
+	Debug("before scope")
+
+	restore := WithLevel("debug")
+	Debug("in scope")
+	if EffectiveConfig().Level != "debug" {
+		t.Errorf("expected debug level in scope, got %s", EffectiveConfig().Level)
+	}
+	restore()
+	Debug("after scope")
+
+	lines := readLogLines(t, filename)
+	if findLogLine(lines, "before scope") != nil || findLogLine(lines, "after scope") != nil {
+		t.Error("expected debug entries outside scope to be dropped")
+	}
+	if findLogLine(lines, "in scope") == nil {
+		t.Error("expected debug entry in scope")
+	}
+	if EffectiveConfig().Level != "warn" {
+		t.Errorf("expected warn level after restore, got %s", EffectiveConfig().Level)
+	}
+}
+
+func TestWithLevelRestoreAfterInit(t *testing.T) {
+	initTestLogger(t, WithLogLevel("warn"))
+	restore := WithLevel("debug")
+
+	// 重新初始化后恢复不覆盖新的日志级别和配置
+	filename := initTestLogger(t, WithLogLevel("error"))
+	restore()
+	if EffectiveConfig().Level != "error" {
+		t.Errorf("expected error level after restore, got %s", EffectiveConfig().Level)
+	}
+	Warn("warn after restore")
+	if findLogLine(readLogLines(t, filename), "warn after restore") != nil {
+		t.Error("expected warn entry to be dropped at error level")
+	}
+
+	// 日志级别已被再次修改时不恢复
+	restore = WithLevel("info")
+	WithLevel("debug")
+	restore()
+	if EffectiveConfig().Level != "debug" {
+		t.Errorf("expected debug level to be kept, got %s", EffectiveConfig().Level)
+	}
+}
@@ -0,0 +1,229 @@
+package logger
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var logfmtPool = buffer.NewPool()
+
+// logfmt格式的encoder，每条日志输出为一行key=value，例如：ts=... level=info msg="charge succeeded" user_id=42，
+// 值包含空格、等号、引号或控制字符时加双引号并转义，对象、数组等复合类型的值输出为json字符串
+type logfmtEncoder struct {
+	cfg       zapcore.EncoderConfig
+	buf       *buffer.Buffer // With添加的字段，已编码
+	namespace string         // OpenNamespace添加的key前缀
+}
+
+func newLogfmtEncoder(cfg zapcore.EncoderConfig) *logfmtEncoder {
+	return &logfmtEncoder{cfg: cfg, buf: logfmtPool.Get()}
+}
+
+func (e *logfmtEncoder) Clone() zapcore.Encoder {
+	return e.clone()
+}
+
+func (e *logfmtEncoder) clone() *logfmtEncoder {
+	c := &logfmtEncoder{cfg: e.cfg, buf: logfmtPool.Get(), namespace: e.namespace}
+	_, _ = c.buf.Write(e.buf.Bytes())
+	return c
+}
+
+func (e *logfmtEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	out := logfmtPool.Get()
+	base := &logfmtEncoder{cfg: e.cfg, buf: out}
+
+	keys, values := (&orderedEncoder{cfg: e.cfg, order: defaultFieldOrder}).baseFields(ent)
+	for i, key := range keys {
+		base.addKey(key)
+		base.appendString(toString(values[i]))
+	}
+
+	enc := e.clone()
+	defer enc.buf.Free()
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	if ent.Stack != "" && e.cfg.StacktraceKey != "" {
+		enc.AddString(e.cfg.StacktraceKey, ent.Stack)
+	}
+	if enc.buf.Len() > 0 {
+		if out.Len() > 0 {
+			out.AppendByte(' ')
+		}
+		_, _ = out.Write(enc.buf.Bytes())
+	}
+
+	if e.cfg.LineEnding != "" {
+		out.AppendString(e.cfg.LineEnding)
+	} else {
+		out.AppendString(zapcore.DefaultLineEnding)
+	}
+	return out, nil
+}
+
+// 写入key，key中的空白、等号和引号替换为下划线
+func (e *logfmtEncoder) addKey(key string) {
+	if e.buf.Len() > 0 {
+		e.buf.AppendByte(' ')
+	}
+	key = e.namespace + key
+	if key == "" {
+		key = "_"
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		e.buf.AppendString(string(r))
+	}
+	e.buf.AppendByte('=')
+}
+
+// 写入字符串值，需要时加引号
+func (e *logfmtEncoder) appendString(s string) {
+	if logfmtNeedsQuote(s) {
+		e.buf.AppendString(strconv.Quote(s))
+		return
+	}
+	e.buf.AppendString(s)
+}
+
+func logfmtNeedsQuote(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// 写入json格式的值
+func (e *logfmtEncoder) appendJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	e.appendString(string(data))
+	return nil
+}
+
+func (e *logfmtEncoder) AddArray(key string, v zapcore.ArrayMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := m.AddArray(key, v); err != nil {
+		return err
+	}
+	e.addKey(key)
+	return e.appendJSON(m.Fields[key])
+}
+
+func (e *logfmtEncoder) AddObject(key string, v zapcore.ObjectMarshaler) error {
+	m := zapcore.NewMapObjectEncoder()
+	if err := v.MarshalLogObject(m); err != nil {
+		return err
+	}
+	e.addKey(key)
+	return e.appendJSON(m.Fields)
+}
+
+func (e *logfmtEncoder) AddReflected(key string, v interface{}) error {
+	e.addKey(key)
+	return e.appendJSON(v)
+}
+
+func (e *logfmtEncoder) AddBinary(key string, v []byte) {
+	e.AddString(key, base64.StdEncoding.EncodeToString(v))
+}
+
+func (e *logfmtEncoder) AddByteString(key string, v []byte) {
+	e.AddString(key, string(v))
+}
+
+func (e *logfmtEncoder) AddBool(key string, v bool) {
+	e.addKey(key)
+	e.buf.AppendBool(v)
+}
+
+func (e *logfmtEncoder) AddComplex128(key string, v complex128) {
+	e.addKey(key)
+	e.buf.AppendString(strconv.FormatComplex(v, 'f', -1, 128))
+}
+
+func (e *logfmtEncoder) AddComplex64(key string, v complex64) {
+	e.addKey(key)
+	e.buf.AppendString(strconv.FormatComplex(complex128(v), 'f', -1, 64))
+}
+
+func (e *logfmtEncoder) AddDuration(key string, v time.Duration) {
+	arr := &primitiveCapture{}
+	if e.cfg.EncodeDuration != nil {
+		e.cfg.EncodeDuration(v, arr)
+	}
+	if len(arr.values) == 0 {
+		arr.AppendString(v.String())
+	}
+	e.addKey(key)
+	e.appendString(toString(arr.values[0]))
+}
+
+func (e *logfmtEncoder) AddFloat64(key string, v float64) {
+	e.addKey(key)
+	e.buf.AppendFloat(v, 64)
+}
+
+func (e *logfmtEncoder) AddFloat32(key string, v float32) {
+	e.addKey(key)
+	e.buf.AppendFloat(float64(v), 32)
+}
+
+func (e *logfmtEncoder) AddInt(key string, v int)     { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt32(key string, v int32) { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt16(key string, v int16) { e.AddInt64(key, int64(v)) }
+func (e *logfmtEncoder) AddInt8(key string, v int8)   { e.AddInt64(key, int64(v)) }
+
+func (e *logfmtEncoder) AddInt64(key string, v int64) {
+	e.addKey(key)
+	e.buf.AppendInt(v)
+}
+
+func (e *logfmtEncoder) AddString(key, v string) {
+	e.addKey(key)
+	e.appendString(v)
+}
+
+func (e *logfmtEncoder) AddTime(key string, v time.Time) {
+	arr := &primitiveCapture{}
+	if e.cfg.EncodeTime != nil {
+		e.cfg.EncodeTime(v, arr)
+	}
+	if len(arr.values) == 0 {
+		arr.AppendString(v.Format(time.RFC3339Nano))
+	}
+	e.addKey(key)
+	e.appendString(toString(arr.values[0]))
+}
+
+func (e *logfmtEncoder) AddUint(key string, v uint)       { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint32(key string, v uint32)   { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint16(key string, v uint16)   { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUint8(key string, v uint8)     { e.AddUint64(key, uint64(v)) }
+func (e *logfmtEncoder) AddUintptr(key string, v uintptr) { e.AddUint64(key, uint64(v)) }
+
+func (e *logfmtEncoder) AddUint64(key string, v uint64) {
+	e.addKey(key)
+	e.buf.AppendUint(v)
+}
+
+func (e *logfmtEncoder) OpenNamespace(key string) {
+	e.namespace += key + "."
+}
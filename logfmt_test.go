@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogfmtEncoder(t *testing.T) {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	cfg.EncodeDuration = zapcore.StringDurationEncoder
+	enc := newLogfmtEncoder(cfg).Clone()
+	enc.AddString("service", "billing")
+
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Time: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC), Message: "charge succeeded"}
+	buf, err := enc.EncodeEntry(ent, []Field{
+		Int("user_id", 42),
+		String("plain", "abc"),
+		String("quoted", `say "hi" a=b`),
+		String("empty", ""),
+		String("multi line", "a\nb"),
+		Bool("ok", true),
+		Duration("cost", 340*time.Millisecond),
+		Int64s("ids", []int64{1, 2}),
+		Err(errors.New("card declined")),
+		zap.Namespace("req"),
+		String("id", "r-1"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `ts=2024-06-01T12:00:00.000Z level=info msg="charge succeeded" service=billing user_id=42 plain=abc ` +
+		`quoted="say \"hi\" a=b" empty="" multi_line="a\nb" ok=true cost=340ms ids=[1,2] error="card declined" req.id=r-1` + "\n"
+	if got := buf.String(); got != want {
+		t.Errorf("unexpected logfmt line\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestWithEncodingLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Init(WithEncoding("logfmt"), WithTee(&buf)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetLogger(t) })
+
+	Info("logfmt entry", String("path", "/users"))
+	line := buf.String()[strings.Index(buf.String(), "\n")+1:] // 跳过初始化日志
+	if !strings.HasPrefix(line, "ts=") || !strings.Contains(line, ` level=info `) ||
+		!strings.Contains(line, `msg="logfmt entry" path=/users`) {
+		t.Errorf("unexpected logfmt output %q", line)
+	}
+	if err := SetEncoding("json"); err == nil {
+		t.Error("expected error switching encoding of logfmt logger")
+	}
+}
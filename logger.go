@@ -2,27 +2,149 @@ package logger
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"log"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
-var defaultLogger *zap.Logger
+var (
+	defaultLogger *zap.Logger
+	loggerMu      sync.RWMutex // 保护defaultLogger
+	closeLogger   func()       // 关闭defaultLogger打开的文件
+)
+
+// 缓存的跳过一层调用的logger，base为对应的defaultLogger
+type skipLogger struct {
+	base   *zap.Logger
+	logger *zap.Logger
+}
 
+var cachedSkipLogger atomic.Pointer[skipLogger]
+
+// 获取跳过一层调用的defaultLogger，defaultLogger不变时使用缓存，避免每次调用都复制logger
 func getLogger() *zap.Logger {
-	if defaultLogger == nil {
-		err := InitLogger(false, "", "debug") // 默认输出到控台
-		if err != nil {
-			log.Fatal(err)
-		}
+	base := loadLogger()
+	if c := cachedSkipLogger.Load(); c != nil && c.base == base {
+		return c.logger
+	}
+
+	logger := base.WithOptions(zap.AddCallerSkip(1))
+	cachedSkipLogger.Store(&skipLogger{base: base, logger: logger})
+	return logger
+}
+
+// 获取defaultLogger，未初始化时以默认配置初始化
+func loadLogger() *zap.Logger {
+	loggerMu.RLock()
+	logger := defaultLogger
+	loggerMu.RUnlock()
+	if logger != nil {
+		return logger
+	}
+
+	if strictInit.Load() {
+		panic("logger: log function called before InitLogger or Init")
+	}
+
+	err := InitLogger(false, "", "debug") // 默认输出到控台
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return defaultLogger
+}
+
+// IsInitialized 全局logger是否已经初始化，不会触发默认初始化，库可以据此决定是否自己设置默认配置
+func IsInitialized() bool {
+	loggerMu.RLock()
+	defer loggerMu.RUnlock()
+	return defaultLogger != nil
+}
+
+var strictInit atomic.Bool
+
+// SetStrictInit 设置为true时，在InitLogger或Init之前调用日志函数会panic，而不是以默认配置初始化，
+// 用于检查初始化顺序，默认为false
+func SetStrictInit(strict bool) {
+	strictInit.Store(strict)
+}
+
+// 随defaultLogger一起生效的全局状态，构建logger时先保存在options中，构建成功后由setLogger一起替换，
+// 避免初始化失败时全局状态指向没有生效的logger
+type loggerState struct {
+	mainOutput   *swapWriteSyncer
+	stderrOutput *swapWriteSyncer
+	encoding     *encodingSwitch
+	sampling     *samplingState
+	samplingKey  string
+	audit        *zap.Logger
+	ctxField     bool
+
+	compactInts   int64
+	largeAnyLimit int64
+	anyEncoder    *func(interface{}) ([]byte, error)
+}
+
+// 当前生效的全局状态
+func loadState() loggerState {
+	key, _ := samplingCtxKey.Load().(string)
+	return loggerState{
+		mainOutput:    mainOutput.Load(),
+		stderrOutput:  stderrOutput.Load(),
+		encoding:      activeEncoding.Load(),
+		sampling:      activeSampling.Load(),
+		samplingKey:   key,
+		audit:         auditLogger.Load(),
+		ctxField:      ctxFieldEnabled.Load(),
+		compactInts:   compactIntsLimit.Load(),
+		largeAnyLimit: largeAnyLimit.Load(),
+		anyEncoder:    anyEncoder.Load(),
 	}
+}
 
-	return defaultLogger.WithOptions(zap.AddCallerSkip(1))
+func (s loggerState) store() {
+	mainOutput.Store(s.mainOutput)
+	stderrOutput.Store(s.stderrOutput)
+	activeEncoding.Store(s.encoding)
+	activeSampling.Store(s.sampling)
+	samplingCtxKey.Store(s.samplingKey)
+	auditLogger.Store(s.audit)
+	ctxFieldEnabled.Store(s.ctxField)
+	compactIntsLimit.Store(s.compactInts)
+	largeAnyLimit.Store(s.largeAnyLimit)
+	anyEncoder.Store(s.anyEncoder)
+}
+
+// 替换defaultLogger、生效的配置和全局状态，closeFn为关闭logger打开的文件的函数，替换后刷新并关闭原来的logger打开的文件
+func setLogger(logger *zap.Logger, cfg Config, closeFn func(), state loggerState) {
+	loggerMu.Lock()
+	old, oldClose := defaultLogger, closeLogger
+	defaultLogger = logger
+	effectiveConfig = cfg
+	closeLogger = closeFn
+	state.store()
+	loggerMu.Unlock()
+
+	if old != nil {
+		_ = old.Sync()
+	}
+	if oldClose != nil {
+		oldClose()
+	}
 }
 
 // InitLogger 初始化日志
@@ -34,8 +156,27 @@ func getLogger() *zap.Logger {
 // 		以json数据格式输出到控台，eg: InitLogger(false, "", "debug", "json")
 // 		以json数据格式输出到文件，eg: InitLogger(true, "out.log", "debug")
 func InitLogger(isSave bool, filename string, level string, encodingType ...string) error {
+	opts := []Option{WithLogLevel(level)}
+	if isSave {
+		opts = append(opts, WithSave(filename))
+	}
+	if len(encodingType) > 0 {
+		opts = append(opts, WithEncoding(encodingType[0]))
+	}
+
+	return Init(opts...)
+}
+
+// Init 根据选项初始化日志，不传选项时以console数据格式输出debug级别日志到控台
+// 		以json数据格式输出info级别日志到控台，eg: Init(WithLogLevel("info"), WithEncoding("json"))
+// 		以json数据格式输出到文件，eg: Init(WithSave("out.log"))
+func Init(opts ...Option) error {
 	log.SetFlags(log.Lmicroseconds | log.Lshortfile | log.LstdFlags) // log包显示设置
 
+	o := defaultOptions()
+	o.apply(opts...)
+	isSave, filename, level := o.isSave, o.filename, o.level
+
 	// 保存日志路径
 	if isSave && filename == "" {
 		filename = "out.log" // 默认
@@ -68,9 +209,10 @@ func InitLogger(isSave bool, filename string, level string, encodingType ...stri
       		"errorOutputPaths": ["%s"]
       	}`, levelName, encoding, filename, filename)
 	} else { // 在控台输出日志
-		if len(encodingType) > 0 && encodingType[0] == "json" { // 控台模式下可以输出json格式，也可以输出console模式
-			encoding = "json"
-		} else {
+		switch o.encoding { // 控台模式下可以输出json格式，也可以输出console或logfmt格式
+		case "json", "logfmt":
+			encoding = o.encoding
+		default:
 			encoding = "console"
 		}
 
@@ -89,16 +231,20 @@ func InitLogger(isSave bool, filename string, level string, encodingType ...stri
 	}
 
 	config.EncoderConfig = zap.NewProductionEncoderConfig()
+	config.InitialFields = o.initialFields
 
 	config.EncoderConfig.EncodeTime = timeFormatter // 默认时间格式
-	if isSave {
+	if isSave || encoding == "logfmt" {
 		config.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	}
+	o.setEncoderConfig(&config.EncoderConfig)
 
-	defaultLogger, err = config.Build()
+	logger, err := o.build(config)
 	if err != nil {
+		o.close()
 		return err
 	}
+	setLogger(logger, o.effectiveConfig(config), o.close, o.state)
 
 	// 打印log配置结果
 	if isSave {
@@ -114,6 +260,28 @@ func timeFormatter(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 	enc.AppendString(t.Format("2006-01-02 15:04:05.000"))
 }
 
+// NewLoggerWithEncoder 使用自定义的encoder和输出新建logger，用于支持其他输出格式，例如GELF、CEF，
+// 返回的logger与全局logger无关，level为DEBUG, INFO, WARN, ERROR
+func NewLoggerWithEncoder(enc zapcore.Encoder, ws zapcore.WriteSyncer, level string) (*ZapLogger, error) {
+	lvl, err := zapcore.ParseLevel(strings.ToLower(level))
+	if err != nil {
+		return nil, err
+	}
+
+	core := zapcore.NewCore(enc, ws, lvl)
+	return zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel), zap.WithFatalHook(fatalHook{})), nil
+}
+
+// Nop 返回丢弃所有日志的logger，可以用于基准测试或者需要关闭日志的库
+func Nop() *ZapLogger {
+	return zap.NewNop()
+}
+
+// InitNop 设置全局logger为丢弃所有日志的logger，包级别的日志函数都不再输出
+func InitNop() {
+	setLogger(Nop(), Config{}, nil, loggerState{})
+}
+
 // Ctx logs trace info
 // X-B3-TraceId：一条请求链路（Trace）的唯一标识，必须值
 // X-B3-SpanId：一个工作单元（Span）的唯一标识，必须值
@@ -121,22 +289,142 @@ func timeFormatter(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
 // X-B3-Sampled：是否被抽样输出的标志，1表示需要被输出，0表示不需要被输出
 // X-Span-Name：工作单元的名称
 func Ctx(ctx context.Context) *zap.Logger {
-	fieldsMap := make(map[string]interface{})
+	return ctxLogger(getLogger(), ctx)
+}
+
+// CtxWith 与Ctx(ctx).With(fields...)相同，链路信息和fields只调用一次With，减少热点路径的内存分配，
+// 返回的logger直接调用Info等方法时显示正确的调用位置
+//	eg: CtxWith(ctx, String("order_id", id)).Info("create order")
+func CtxWith(ctx context.Context, fields ...Field) *zap.Logger {
+	return ctxLogger(loadLogger(), ctx, fields...)
+}
+
+// CtxFrom 在已经添加字段的logger上添加context中的链路信息，ZapLogger是zap.Logger的别名，不能添加方法，
+// 所以以logger作为参数
+//	eg: base := WithFields(String("svc", "api"))
+//	    CtxFrom(base, ctx).Info("request")
+func CtxFrom(logger *ZapLogger, ctx context.Context) *ZapLogger {
+	return ctxLogger(logger, ctx)
+}
+
+// 在logger上添加context中的链路信息和extra字段
+func ctxLogger(logger *zap.Logger, ctx context.Context, extra ...Field) *zap.Logger {
+	fieldsMap := make(map[string]string)
 	keys := []string{"X-B3-TraceId", "X-B3-SpanId", "X-B3-ParentSpanId", "X-Span-Name"}
 
 	if ctx != nil {
 		for _, key := range keys {
-			if v := ctx.Value(key); v != nil {
+			switch v := ctx.Value(key).(type) {
+			case nil:
+			case string:
 				fieldsMap[key] = v
+			default:
+				fieldsMap[key] = fmt.Sprint(v)
 			}
 		}
 	}
 
+	if ctx != nil {
+		if _, ok := levelFromContext(ctx); ok { // 按请求临时调整日志级别
+			logger = logger.WithOptions(replaceLevel(func(enab zapcore.LevelEnabler) zapcore.LevelEnabler {
+				return contextLevelEnabler{ctx: ctx, base: enab}
+			}))
+		}
+	}
+
+	var fields []Field
 	if len(fieldsMap) > 0 {
-		return getLogger().With(Any("context", fieldsMap))
+		fields = append(fields, Map("context", fieldsMap))
+	}
+	if ctx != nil {
+		fields = append(fields, contextFields(ctx)...)
+		if f, ok := samplingKeyFromContext(ctx); ok {
+			fields = append(fields, f)
+		}
+		if ctxFieldEnabled.Load() {
+			fields = append(fields, contextField(ctx))
+		}
 	}
+	fields = append(fields, extra...)
+
+	if len(fields) > 0 {
+		return logger.With(fields...)
+	}
+
+	return logger
+}
+
+// 是否设置了WithCore，设置后Ctx会把context传递给扩展的core，用于关联trace和span id
+var ctxFieldEnabled atomic.Bool
 
-	return getLogger()
+// 携带context的字段，只用于扩展的core提取trace和span id，其他core忽略该字段
+func contextField(ctx context.Context) Field {
+	return Field{Key: "ctx", Type: zapcore.SkipType, Interface: ctx}
+}
+
+var (
+	contextFieldKeys   map[string]string
+	contextFieldKeysMu sync.RWMutex
+)
+
+// SetContextFieldKeys 设置Ctx从context读取的key和对应的日志字段名，每个存在的值作为单独的字段输出，
+//	eg: SetContextFieldKeys(map[string]string{"tenantID": "tenant_id", "userID": "user_id"})
+func SetContextFieldKeys(keys map[string]string) {
+	m := make(map[string]string, len(keys))
+	for k, v := range keys {
+		m[k] = v
+	}
+
+	contextFieldKeysMu.Lock()
+	contextFieldKeys = m
+	contextFieldKeysMu.Unlock()
+}
+
+// 从context读取SetContextFieldKeys设置的字段，按字段名排序
+func contextFields(ctx context.Context) []Field {
+	contextFieldKeysMu.RLock()
+	defer contextFieldKeysMu.RUnlock()
+
+	var fields []Field
+	for key, name := range contextFieldKeys {
+		if v := ctx.Value(key); v != nil {
+			fields = append(fields, Any(name, v))
+		}
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields
+}
+
+// LevelOverrideKey context中临时调整日志级别的key，值为日志级别，例如："debug"或zapcore.DebugLevel，
+// 只对使用Ctx(ctx)获取的logger生效，不影响全局日志级别
+const LevelOverrideKey = "log-level-override"
+
+// 从context读取临时日志级别
+func levelFromContext(ctx context.Context) (zapcore.Level, bool) {
+	switch v := ctx.Value(LevelOverrideKey).(type) {
+	case zapcore.Level:
+		return v, true
+	case string:
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(strings.ToLower(v))); err == nil {
+			return level, true
+		}
+	}
+
+	return zapcore.DebugLevel, false
+}
+
+// 根据context判断日志级别是否输出，context没有设置临时日志级别时使用原来的级别
+type contextLevelEnabler struct {
+	ctx  context.Context
+	base zapcore.LevelEnabler
+}
+
+func (e contextLevelEnabler) Enabled(level zapcore.Level) bool {
+	if lvl, ok := levelFromContext(e.ctx); ok {
+		return level >= lvl
+	}
+	return e.base.Enabled(level)
 }
 
 // ----------------------------------重新封装zap的log----------------------------------------
@@ -196,6 +484,89 @@ func Fatalf(format string, a ...interface{}) {
 	getLogger().Fatal(fmt.Sprintf(format, a...))
 }
 
+// Debugln 参数之间用空格连接的debug级别信息，与fmt.Sprintln相同但去掉末尾的换行
+func Debugln(a ...interface{}) {
+	getLogger().Sugar().Debugln(a...)
+}
+
+// Infoln 参数之间用空格连接的info级别信息，eg: Infoln("user", id, "logged in")
+func Infoln(a ...interface{}) {
+	getLogger().Sugar().Infoln(a...)
+}
+
+// Warnln 参数之间用空格连接的warn级别信息
+func Warnln(a ...interface{}) {
+	getLogger().Sugar().Warnln(a...)
+}
+
+// Errorln 参数之间用空格连接的error级别信息
+func Errorln(a ...interface{}) {
+	getLogger().Sugar().Errorln(a...)
+}
+
+// AddGlobalFields 在已初始化的logger上添加全局字段，之后输出的日志都携带这些字段，多次调用时字段累加，
+// 重新初始化logger后需要重新添加
+func AddGlobalFields(fields ...Field) {
+	updateLogger(func(logger *zap.Logger) *zap.Logger {
+		return logger.With(fields...)
+	})
+}
+
+// 用fn修改defaultLogger，未初始化时以默认配置初始化，
+// 加锁后defaultLogger可能已经被并发的Close置空，此时重新初始化
+func updateLogger(fn func(*zap.Logger) *zap.Logger) {
+	for {
+		loadLogger()
+
+		loggerMu.Lock()
+		if defaultLogger != nil {
+			defaultLogger = fn(defaultLogger)
+			loggerMu.Unlock()
+			return
+		}
+		loggerMu.Unlock()
+	}
+}
+
+// Sync 刷新缓存的日志到输出，程序退出前调用
+func Sync() error {
+	return loadLogger().Sync()
+}
+
+// Close 刷新缓存的日志并关闭logger打开的文件，之后输出日志时以默认配置重新初始化，
+// 用于测试或者重新配置日志前释放文件句柄
+func Close() error {
+	loggerMu.Lock()
+	logger, closeFn := defaultLogger, closeLogger
+	defaultLogger, closeLogger = nil, nil
+	auditLogger.Store(nil) // 审计日志文件随logger一起关闭
+	loggerMu.Unlock()
+
+	if logger == nil {
+		return nil
+	}
+	err := logger.Sync()
+	if closeFn != nil {
+		closeFn()
+	}
+	return err
+}
+
+// Print 兼容标准库log.Print，输出info级别信息
+func Print(a ...interface{}) {
+	getLogger().Info(fmt.Sprint(a...))
+}
+
+// Printf 兼容标准库log.Printf，输出info级别信息
+func Printf(format string, a ...interface{}) {
+	getLogger().Info(fmt.Sprintf(format, a...))
+}
+
+// Println 兼容标准库log.Println，输出info级别信息，去掉末尾的换行
+func Println(a ...interface{}) {
+	getLogger().Info(strings.TrimSuffix(fmt.Sprintln(a...), "\n"))
+}
+
 // WithFields 携带字段信息
 func WithFields(fields ...Field) *zap.Logger {
 	return getLogger().With(fields...)
@@ -214,11 +585,24 @@ func Int(key string, val int) Field {
 	return zap.Int(key, val)
 }
 
-// Int64 int64类型
+// Int64 int64类型，json格式输出精确的整数，不会使用科学计数法
 func Int64(key string, val int64) Field {
 	return zap.Int64(key, val)
 }
 
+// Int64NonZero int64类型，val为0时不输出该字段
+func Int64NonZero(key string, val int64) Field {
+	if val == 0 {
+		return zap.Skip()
+	}
+	return zap.Int64(key, val)
+}
+
+// IDString int64类型的id以字符串输出，例如雪花id，避免js等以浮点数解析时丢失精度
+func IDString(key string, id int64) Field {
+	return zap.String(key, strconv.FormatInt(id, 10))
+}
+
 // Uint uint类型
 func Uint(key string, val uint) Field {
 	return zap.Uint(key, val)
@@ -249,6 +633,37 @@ func String(key string, val string) Field {
 	return zap.String(key, val)
 }
 
+// StringNonEmpty string类型，val为空字符串时不输出该字段
+func StringNonEmpty(key string, val string) Field {
+	if val == "" {
+		return zap.Skip()
+	}
+	return zap.String(key, val)
+}
+
+// Hex []byte类型，输出为小写十六进制字符串，例如签名、哈希值，b为空时输出空字符串
+func Hex(key string, b []byte) Field {
+	return zap.String(key, hex.EncodeToString(b))
+}
+
+// Base64URL []byte类型，输出为URL安全的base64字符串(不带填充)，b为空时输出空字符串
+func Base64URL(key string, b []byte) Field {
+	return zap.String(key, base64.RawURLEncoding.EncodeToString(b))
+}
+
+// Ptr 指针地址，输出为十六进制字符串，例如"0xc000012345"，p为nil时输出"0x0"，
+// 支持指针、map、slice、chan、func类型，其他类型同Any
+func Ptr(key string, p interface{}) Field {
+	if p == nil {
+		return zap.String(key, "0x0")
+	}
+	switch v := reflect.ValueOf(p); v.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func:
+		return zap.String(key, fmt.Sprintf("0x%x", v.Pointer()))
+	}
+	return Any(key, p)
+}
+
 // Stringer stringer类型
 func Stringer(key string, val fmt.Stringer) Field {
 	return zap.Stringer(key, val)
@@ -259,29 +674,124 @@ func Time(key string, val time.Time) Field {
 	return zap.Time(key, val)
 }
 
+// TimeLayout time.Time类型，按layout格式化输出，与日志时间格式无关，零值时输出空字符串
+func TimeLayout(key string, val time.Time, layout string) Field {
+	if val.IsZero() {
+		return zap.String(key, "")
+	}
+	return zap.String(key, val.Format(layout))
+}
+
 // Duration time.Duration类型
 func Duration(key string, val time.Duration) Field {
 	return zap.Duration(key, val)
 }
 
-// Err err类型
+// DurationHuman time.Duration类型，输出为易读的字符串，例如"340ms"、"1.5s"、"2m30s"，与全局的时间间隔格式无关，
+// 大于1秒时精确到毫秒，大于1毫秒时精确到微秒
+func DurationHuman(key string, d time.Duration) Field {
+	switch abs := d.Abs(); {
+	case abs >= time.Second:
+		d = d.Round(time.Millisecond)
+	case abs >= time.Millisecond:
+		d = d.Round(time.Microsecond)
+	}
+	return zap.String(key, d.String())
+}
+
+// Stringp *string类型，nil时输出null
+func Stringp(key string, val *string) Field {
+	return zap.Stringp(key, val)
+}
+
+// Intp *int类型，nil时输出null
+func Intp(key string, val *int) Field {
+	return zap.Intp(key, val)
+}
+
+// Int64p *int64类型，nil时输出null
+func Int64p(key string, val *int64) Field {
+	return zap.Int64p(key, val)
+}
+
+// Uint64p *uint64类型，nil时输出null
+func Uint64p(key string, val *uint64) Field {
+	return zap.Uint64p(key, val)
+}
+
+// Float64p *float64类型，nil时输出null
+func Float64p(key string, val *float64) Field {
+	return zap.Float64p(key, val)
+}
+
+// Boolp *bool类型，nil时输出null
+func Boolp(key string, val *bool) Field {
+	return zap.Boolp(key, val)
+}
+
+// Timep *time.Time类型，nil时输出null
+func Timep(key string, val *time.Time) Field {
+	return zap.Timep(key, val)
+}
+
+// Durationp *time.Duration类型，nil时输出null
+func Durationp(key string, val *time.Duration) Field {
+	return zap.Durationp(key, val)
+}
+
+// Err err类型，err通过WrapCode附加了错误码时同时输出error_code字段
 func Err(err error) Field {
+	if code := ErrorCode(err); code != "" {
+		return zap.Inline(codedErrField{code: code, err: err})
+	}
 	return zap.Error(err)
 }
 
 // Any 任意类型，如果是对象、slice、map等复合类型，使用Any
 func Any(key string, val interface{}) Field {
+	if limit := largeAnyLimit.Load(); limit > 0 {
+		warnLargeAny(key, val, int(limit))
+	}
 	return zap.Any(key, val)
 }
 
-// GetLogger 获取defaultLogger，设置caller值才能正确的显示对应的代码行数
-func GetLogger(skip int) *zap.Logger {
-	if defaultLogger == nil {
-		err := InitLogger(false, "", "debug") // 默认输出到控台
-		if err != nil {
-			log.Fatal(err)
-		}
+// StringMap map[string]string类型，按key排序输出，不使用反射
+func StringMap(key string, m map[string]string) Field {
+	return zap.Object(key, stringMap(m))
+}
+
+// Map map[string]string类型，同StringMap
+func Map(key string, m map[string]string) Field {
+	return StringMap(key, m)
+}
+
+type stringMap map[string]string
+
+func (m stringMap) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		enc.AddString(k, m[k])
+	}
+	return nil
+}
+
+// FieldsToMap 把字段转换为map，方便在测试中检查字段的值，不需要解析json，
+// 对象输出为map[string]interface{}，数组输出为[]interface{}
+//	eg: m := FieldsToMap(Int("user_id", 42)) // m["user_id"] == 42
+func FieldsToMap(fields ...Field) map[string]interface{} {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
 	}
+	return enc.Fields
+}
 
-	return defaultLogger.WithOptions(zap.AddCallerSkip(skip))
+// GetLogger 获取defaultLogger，设置caller值才能正确的显示对应的代码行数
+func GetLogger(skip int) *zap.Logger {
+	return loadLogger().WithOptions(zap.AddCallerSkip(skip))
 }
\ No newline at end of file
@@ -1,8 +1,20 @@
 package logger
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
 )
 
 type people struct {
@@ -29,6 +41,516 @@ func TestLogger(t *testing.T) {
 	Info("err is not equal nil ", Any("object", ps))
 }
 
+// 初始化输出到临时文件的logger，测试结束后恢复默认logger
+func initTestLogger(t *testing.T, opts ...Option) string {
+	filename := filepath.Join(t.TempDir(), "out.log")
+	if err := Init(append([]Option{WithSave(filename)}, opts...)...); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetLogger(t) })
+	return filename
+}
+
+// 关闭全局logger打开的文件并清空生效的配置，测试结束时调用，避免影响之后的测试
+func resetLogger(tb testing.TB) {
+	tb.Helper()
+	_ = Close()
+	setLogger(nil, Config{}, nil, loggerState{})
+}
+
+// 读取json格式日志文件的所有行
+func readLogLines(t *testing.T, filename string) []map[string]interface{} {
+	f, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		m := make(map[string]interface{})
+		if err := json.Unmarshal(scanner.Bytes(), &m); err != nil {
+			t.Fatalf("invalid json line %q: %v", scanner.Text(), err)
+		}
+		lines = append(lines, m)
+	}
+	return lines
+}
+
+// 查找指定msg的日志行
+func findLogLine(lines []map[string]interface{}, msg string) map[string]interface{} {
+	for _, line := range lines {
+		if line["msg"] == msg {
+			return line
+		}
+	}
+	return nil
+}
+
+func TestCtxLevelOverride(t *testing.T) {
+	filename := initTestLogger(t, WithLogLevel("info"))
+
+	ctx := context.WithValue(context.Background(), LevelOverrideKey, "debug")
+	Ctx(ctx).Debug("debug with override")
+	Ctx(context.Background()).Debug("debug without override")
+	Debug("debug global")
+
+	lines := readLogLines(t, filename)
+	if findLogLine(lines, "debug with override") == nil {
+		t.Error("expected debug entry with level override")
+	}
+	if findLogLine(lines, "debug without override") != nil || findLogLine(lines, "debug global") != nil {
+		t.Error("level override should only apply to the request context")
+	}
+}
+
+func TestCtxWith(t *testing.T) {
+	filename := initTestLogger(t)
+
+	ctx := context.WithValue(context.Background(), "X-B3-TraceId", "trace-1")
+	CtxWith(ctx, String("order_id", "o-1")).Info("ctx with fields")
+
+	entry := findLogLine(readLogLines(t, filename), "ctx with fields")
+	traceInfo, _ := entry["context"].(map[string]interface{})
+	if traceInfo["X-B3-TraceId"] != "trace-1" || entry["order_id"] != "o-1" {
+		t.Errorf("expected trace info and extra fields, got %v", entry)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "logger_test.go") {
+		t.Errorf("expected caller in logger_test.go, got %v", entry["caller"])
+	}
+}
+
+func TestCtxFrom(t *testing.T) {
+	filename := initTestLogger(t)
+	SetContextFieldKeys(map[string]string{"tenantID": "tenant_id"})
+	defer SetContextFieldKeys(nil)
+
+	base := WithFields(String("svc", "api"))
+	ctx := context.WithValue(context.Background(), "X-B3-TraceId", "trace-1")
+	ctx = context.WithValue(ctx, "tenantID", "t-1")
+	CtxFrom(base, ctx).Info("ctx from base")
+
+	entry := findLogLine(readLogLines(t, filename), "ctx from base")
+	traceInfo, _ := entry["context"].(map[string]interface{})
+	if entry["svc"] != "api" || traceInfo["X-B3-TraceId"] != "trace-1" || entry["tenant_id"] != "t-1" {
+		t.Errorf("expected base and trace fields, got %v", entry)
+	}
+}
+
+func TestSetContextFieldKeys(t *testing.T) {
+	filename := initTestLogger(t)
+	SetContextFieldKeys(map[string]string{"tenantID": "tenant_id", "userID": "user_id"})
+	defer SetContextFieldKeys(nil)
+
+	ctx := context.WithValue(context.Background(), "tenantID", "t-1")
+	ctx = context.WithValue(ctx, "userID", 42)
+	Ctx(ctx).Info("context fields")
+
+	entry := findLogLine(readLogLines(t, filename), "context fields")
+	if entry["tenant_id"] != "t-1" || entry["user_id"] != float64(42) {
+		t.Errorf("expected tenant_id and user_id fields, got %v", entry)
+	}
+}
+
+func TestSetStrictInit(t *testing.T) {
+	resetLogger(t)
+	SetStrictInit(true)
+	defer SetStrictInit(false)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic when logging before init")
+		}
+	}()
+	Info("log before init")
+}
+
+func TestIsInitialized(t *testing.T) {
+	resetLogger(t)
+	if IsInitialized() {
+		t.Error("expected logger not to be initialized")
+	}
+	if IsInitialized() { // 检查不会触发默认初始化
+		t.Error("expected IsInitialized not to initialize the logger")
+	}
+
+	if err := InitLogger(true, filepath.Join(t.TempDir(), "out.log"), "info"); err != nil {
+		t.Fatal(err)
+	}
+	defer resetLogger(t)
+	if !IsInitialized() {
+		t.Error("expected logger to be initialized after InitLogger")
+	}
+}
+
+// 只输出级别和消息的encoder
+type pipeEncoder struct {
+	zapcore.Encoder
+}
+
+func (e pipeEncoder) Clone() zapcore.Encoder {
+	return pipeEncoder{e.Encoder.Clone()}
+}
+
+func (e pipeEncoder) EncodeEntry(ent zapcore.Entry, _ []Field) (*buffer.Buffer, error) {
+	buf := buffer.NewPool().Get()
+	buf.AppendString(ent.Level.CapitalString() + "|" + ent.Message + "\n")
+	return buf, nil
+}
+
+func TestNewLoggerWithEncoder(t *testing.T) {
+	out := &bytes.Buffer{}
+	enc := pipeEncoder{zapcore.NewJSONEncoder(zapcore.EncoderConfig{})}
+	l, err := NewLoggerWithEncoder(enc, zapcore.AddSync(out), "info")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("hidden")
+	l.Info("custom format")
+
+	if got := out.String(); got != "INFO|custom format\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+
+	if _, err := NewLoggerWithEncoder(enc, zapcore.AddSync(out), "unknown"); err == nil {
+		t.Error("expected error for invalid level")
+	}
+}
+
+func TestPrintln(t *testing.T) {
+	filename := initTestLogger(t)
+	Println("user", 1, "logged in")
+	Printf("user %d logged out", 1)
+
+	lines := readLogLines(t, filename)
+	entry := findLogLine(lines, "user 1 logged in")
+	if entry == nil || entry["level"] != "info" {
+		t.Fatalf("expected info entry without trailing newline, got %v", lines)
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "logger_test.go") {
+		t.Errorf("expected caller in logger_test.go, got %v", entry["caller"])
+	}
+	if findLogLine(lines, "user 1 logged out") == nil {
+		t.Error("expected Printf entry")
+	}
+}
+
+func TestInfoln(t *testing.T) {
+	filename := initTestLogger(t)
+	Debugln("debug", 1, "line")
+	Infoln("user", 1, "logged in")
+	Warnln("warn", 2.5)
+	Errorln("error", true)
+
+	lines := readLogLines(t, filename)
+	for msg, level := range map[string]string{"debug 1 line": "debug", "user 1 logged in": "info", "warn 2.5": "warn", "error true": "error"} {
+		entry := findLogLine(lines, msg)
+		if entry == nil || entry["level"] != level {
+			t.Errorf("expected %s entry %q, got %v", level, msg, entry)
+			continue
+		}
+		if caller, _ := entry["caller"].(string); !strings.Contains(caller, "logger_test.go") {
+			t.Errorf("expected caller in logger_test.go, got %v", entry["caller"])
+		}
+	}
+}
+
+func TestFieldsToMap(t *testing.T) {
+	m := FieldsToMap(
+		Int("user_id", 42),
+		String("name", "foo"),
+		Int64s("ids", []int64{1, 2}),
+		Any("people", map[string]interface{}{"name": "张三"}),
+		StringNonEmpty("empty", ""),
+	)
+
+	if m["user_id"] != int64(42) || m["name"] != "foo" {
+		t.Errorf("unexpected scalar fields %v", m)
+	}
+	if ids, _ := m["ids"].([]interface{}); len(ids) != 2 || ids[0] != int64(1) || ids[1] != int64(2) {
+		t.Errorf("unexpected slice field %v", m["ids"])
+	}
+	if p, _ := m["people"].(map[string]interface{}); p["name"] != "张三" {
+		t.Errorf("unexpected any field %v", m["people"])
+	}
+	if _, ok := m["empty"]; ok {
+		t.Error("expected skipped field to be absent")
+	}
+}
+
+func TestNonEmptyFields(t *testing.T) {
+	filename := initTestLogger(t)
+	Info("optional fields", StringNonEmpty("user_id", ""), Int64NonZero("org_id", 0), StringNonEmpty("name", "foo"), Int64NonZero("age", 18))
+
+	entry := findLogLine(readLogLines(t, filename), "optional fields")
+	if _, ok := entry["user_id"]; ok {
+		t.Error("expected empty user_id to be omitted")
+	}
+	if _, ok := entry["org_id"]; ok {
+		t.Error("expected zero org_id to be omitted")
+	}
+	if entry["name"] != "foo" || entry["age"] != float64(18) {
+		t.Errorf("expected non-empty fields, got %v", entry)
+	}
+}
+
+func TestHexBase64URL(t *testing.T) {
+	b := []byte{0xde, 0xad, 0xbe, 0xef, 0xfb, 0xff}
+	m := FieldsToMap(Hex("hex", b), Base64URL("b64", b), Hex("nil_hex", nil), Base64URL("empty_b64", []byte{}))
+
+	if m["hex"] != "deadbeeffbff" {
+		t.Errorf("unexpected hex %v", m["hex"])
+	}
+	if m["b64"] != "3q2-7_v_" {
+		t.Errorf("unexpected base64url %v", m["b64"])
+	}
+	if m["nil_hex"] != "" || m["empty_b64"] != "" {
+		t.Errorf("expected empty strings for empty input, got %v", m)
+	}
+}
+
+func TestPtr(t *testing.T) {
+	p := &people{Name: "foo"}
+	m := FieldsToMap(Ptr("p", p), Ptr("nil", nil), Ptr("map", map[string]int{}), Ptr("value", 1))
+
+	if got, _ := m["p"].(string); got != fmt.Sprintf("%p", p) || !strings.HasPrefix(got, "0x") {
+		t.Errorf("expected pointer address %p, got %v", p, m["p"])
+	}
+	if m["nil"] != "0x0" {
+		t.Errorf("expected 0x0 for nil, got %v", m["nil"])
+	}
+	if got, _ := m["map"].(string); !strings.HasPrefix(got, "0x") {
+		t.Errorf("expected map address, got %v", m["map"])
+	}
+	if m["value"] != int64(1) {
+		t.Errorf("expected non-pointer value to fall back to Any, got %v", m["value"])
+	}
+}
+
+func TestPointerFields(t *testing.T) {
+	filename := initTestLogger(t)
+	name, age, ok := "foo", 18, true
+	Info("pointer fields", Stringp("name", &name), Intp("age", &age), Boolp("ok", &ok),
+		Stringp("nil_name", nil), Intp("nil_age", nil), Boolp("nil_ok", nil), Timep("nil_time", nil))
+
+	entry := findLogLine(readLogLines(t, filename), "pointer fields")
+	if entry["name"] != "foo" || entry["age"] != float64(18) || entry["ok"] != true {
+		t.Errorf("expected pointer values, got %v", entry)
+	}
+	for _, key := range []string{"nil_name", "nil_age", "nil_ok", "nil_time"} {
+		if v, exists := entry[key]; !exists || v != nil {
+			t.Errorf("expected %s to be null, got %v", key, entry)
+		}
+	}
+}
+
+func TestDurationHuman(t *testing.T) {
+	cases := map[time.Duration]string{
+		340 * time.Millisecond:                      "340ms",
+		1500 * time.Millisecond:                     "1.5s",
+		2*time.Minute + 30*time.Second:              "2m30s",
+		1234567891 * time.Nanosecond:                "1.235s",
+		2*time.Millisecond + 345678*time.Nanosecond: "2.346ms",
+		800 * time.Nanosecond:                       "800ns",
+	}
+	for d, want := range cases {
+		if got := FieldsToMap(DurationHuman("cost", d))["cost"]; got != want {
+			t.Errorf("DurationHuman(%d) = %v, want %s", d, got, want)
+		}
+	}
+}
+
+func TestTimeLayout(t *testing.T) {
+	val := time.Date(2024, 3, 5, 14, 30, 0, 0, time.UTC)
+	if got := FieldsToMap(TimeLayout("at", val, "2006-01-02 15:04"))["at"]; got != "2024-03-05 14:30" {
+		t.Errorf("expected formatted time, got %v", got)
+	}
+	// 零值输出空字符串，而不是"0001-01-01 00:00"
+	if got := FieldsToMap(TimeLayout("at", time.Time{}, "2006-01-02 15:04"))["at"]; got != "" {
+		t.Errorf("expected empty string for zero time, got %v", got)
+	}
+}
+
+func TestStringMap(t *testing.T) {
+	filename := initTestLogger(t)
+	Info("headers", Map("headers", map[string]string{"c": "3", "a": "1", "b": "2"}))
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"headers":{"a":"1","b":"2","c":"3"}`; !strings.Contains(string(data), want) {
+		t.Errorf("expected %s in %s", want, data)
+	}
+}
+
+func TestAddGlobalFields(t *testing.T) {
+	filename := initTestLogger(t)
+	Info("before global fields")
+	AddGlobalFields(String("region", "eu-west-1"))
+	AddGlobalFields(String("version", "v1.0.0"))
+	Info("after global fields", String("user", "foo"))
+
+	lines := readLogLines(t, filename)
+	if entry := findLogLine(lines, "before global fields"); entry["region"] != nil {
+		t.Error("global fields should not apply to earlier entries")
+	}
+	entry := findLogLine(lines, "after global fields")
+	if entry["region"] != "eu-west-1" || entry["version"] != "v1.0.0" || entry["user"] != "foo" {
+		t.Errorf("expected global and per-call fields, got %v", entry)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	region, version, user := strings.Index(string(data), `"region"`), strings.Index(string(data), `"version"`), strings.Index(string(data), `"user"`)
+	if !(region < version && version < user) {
+		t.Errorf("expected global fields before per-call fields: %s", data)
+	}
+}
+
+func TestIDString(t *testing.T) {
+	filename := initTestLogger(t)
+	const id = int64(1<<53 + 1) // 超过float64可精确表示的整数范围
+	Info("snowflake id", Int64("id", id), IDString("id_str", id))
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"id":9007199254740993`, `"id_str":"9007199254740993"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("expected %s in %s", want, data)
+		}
+	}
+}
+
+func TestClose(t *testing.T) {
+	filename := initTestLogger(t)
+	Info("before close")
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if isFileOpen(filename) {
+		t.Errorf("expected %s to be closed", filename)
+	}
+	if err := os.Remove(filename); err != nil {
+		t.Errorf("expected log file to be removable: %v", err)
+	}
+
+	Info("after close") // 以默认配置重新初始化
+	if EffectiveConfig().IsSave {
+		t.Error("expected default console logger after close")
+	}
+}
+
+// 文件是否被当前进程打开，不支持/proc时返回false
+func isFileOpen(filename string) bool {
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return false
+	}
+	for _, fd := range fds {
+		if target, _ := os.Readlink(filepath.Join("/proc/self/fd", fd.Name())); target == filename {
+			return true
+		}
+	}
+	return false
+}
+
+func TestInitClosesPreviousLogger(t *testing.T) {
+	first := initTestLogger(t)
+	second := initTestLogger(t)
+	if isFileOpen(first) {
+		t.Errorf("expected %s to be closed after re-init", first)
+	}
+
+	InitNop()
+	if isFileOpen(second) {
+		t.Errorf("expected %s to be closed after InitNop", second)
+	}
+}
+
+func TestGetLoggerCache(t *testing.T) {
+	filename := initTestLogger(t)
+	if getLogger() != getLogger() {
+		t.Error("expected cached logger to be reused")
+	}
+
+	cached := getLogger()
+	AddGlobalFields(String("svc", "api"))
+	if getLogger() == cached {
+		t.Error("expected cache to be invalidated after reconfiguration")
+	}
+	Info("after reconfiguration")
+	if entry := findLogLine(readLogLines(t, filename), "after reconfiguration"); entry["svc"] != "api" {
+		t.Errorf("expected global field, got %v", entry)
+	}
+}
+
+func TestInitNop(t *testing.T) {
+	InitNop()
+	t.Cleanup(func() { resetLogger(t) })
+
+	total := Stats().Total
+	Info("nop info", String("string", "hello golang"))
+	WithFields(Int("int", 1)).Error("nop error")
+	if got := Stats().Total; got != total {
+		t.Errorf("expected no entries, got %d", got-total)
+	}
+	if getLogger().Core().Enabled(zapcore.ErrorLevel) {
+		t.Error("expected nop logger to disable all levels")
+	}
+	if err := Sync(); err != nil {
+		t.Errorf("unexpected sync error: %v", err)
+	}
+}
+
+func BenchmarkNop(b *testing.B) {
+	InitNop()
+	defer resetLogger(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Info("benchmark nop", String("string", "hello golang"))
+	}
+}
+
+func BenchmarkStringMap(b *testing.B) {
+	enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{MessageKey: "msg"})
+	m := map[string]string{"X-B3-TraceId": "trace-1", "X-B3-SpanId": "span-1", "X-Span-Name": "GET /users"}
+	bench := func(field func() Field) func(b *testing.B) {
+		return func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf, _ := enc.EncodeEntry(zapcore.Entry{Message: "map"}, []Field{field()})
+				buf.Free()
+			}
+		}
+	}
+	b.Run("Map", bench(func() Field { return Map("context", m) }))
+	b.Run("Any", bench(func() Field { return Any("context", m) }))
+}
+
+func BenchmarkCtxWith(b *testing.B) {
+	ctx := context.WithValue(context.Background(), "X-B3-TraceId", "trace-1")
+	b.Run("CtxWith", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			CtxWith(ctx, String("order_id", "o-1"))
+		}
+	})
+	b.Run("Ctx.With", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Ctx(ctx).With(String("order_id", "o-1"))
+		}
+	})
+}
+
 func BenchmarkString(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		Info("this is info", String("string", "hello golang"))
@@ -46,3 +568,22 @@ func BenchmarkAny(b *testing.B) {
 		Info("benchmark type any", Any(fmt.Sprintf("object_%d", i), &people{"张三", 11}))
 	}
 }
+
+func TestAddGlobalFieldsConcurrentClose(t *testing.T) {
+	initTestLogger(t)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			AddGlobalFields(String("region", "eu-west-1"))
+		}()
+		go func() {
+			defer wg.Done()
+			_ = Close()
+		}()
+	}
+	wg.Wait()
+	_ = Close()
+}
@@ -0,0 +1,33 @@
+package logger
+
+import "sync"
+
+// 已输出过的key
+var onceKeys sync.Map
+
+// 同一个key只返回一次true
+func firstTime(key string) bool {
+	_, loaded := onceKeys.LoadOrStore(key, struct{}{})
+	return !loaded
+}
+
+// InfoOnce 同一个key在进程内只输出一次info级别信息，例如启动时的提示
+func InfoOnce(key string, msg string, fields ...Field) {
+	if firstTime(key) {
+		getLogger().Info(msg, fields...)
+	}
+}
+
+// WarnOnce 同一个key在进程内只输出一次warn级别信息，例如使用了废弃的配置
+func WarnOnce(key string, msg string, fields ...Field) {
+	if firstTime(key) {
+		getLogger().Warn(msg, fields...)
+	}
+}
+
+// ErrorOnce 同一个key在进程内只输出一次error级别信息
+func ErrorOnce(key string, msg string, fields ...Field) {
+	if firstTime(key) {
+		getLogger().Error(msg, fields...)
+	}
+}
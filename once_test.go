@@ -0,0 +1,21 @@
+package logger
+
+import "testing"
+
+func TestWarnOnce(t *testing.T) {
+	filename := initTestLogger(t)
+	t.Cleanup(func() { onceKeys.Delete("dep") })
+	for i := 0; i < 10; i++ {
+		WarnOnce("dep", "deprecated config used", Int("i", i))
+	}
+
+	count := 0
+	for _, line := range readLogLines(t, filename) {
+		if line["msg"] == "deprecated config used" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected exactly 1 entry, got %d", count)
+	}
+}
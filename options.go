@@ -0,0 +1,885 @@
+package logger
+
+import (
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// Option 日志选项
+type Option func(*options)
+
+type options struct {
+	isSave   bool
+	filename string
+	level    string
+	encoding string
+	tees     []teeOutput
+
+	state loggerState // 构建logger时产生的全局状态，初始化成功后才生效
+
+	failoverFiles []string
+	singleStream  bool
+	writeTimeout  time.Duration
+	messagePrefix string
+	epochField    string
+	stackTrim     []string
+	trimStack     bool
+	rateLimit     int
+
+	fullCaller       bool
+	callerTrimPrefix string
+	callerFields     bool
+	moduleName       string
+
+	eventLogSource string
+	cores          []func() (zapcore.Core, error)
+	journald       bool
+
+	fallback         bool
+	fallbackFilename string
+
+	shortLevels bool
+	severity    bool
+	keepLevel   bool
+	utc         bool
+	color       *bool
+	levelColors map[zapcore.Level]string
+	fieldOrder  []string
+	sortFields  bool
+
+	initialFields map[string]interface{}
+	schemaField   *Field
+	processInfo   bool
+
+	sampling             *samplingOptions
+	samplingKey          string
+	onSampled, onDropped func(zapcore.Entry)
+
+	compactInts int
+
+	rotation *rotationOptions
+	clock    zapcore.Clock
+
+	auditFilename string
+
+	anyEncoder func(interface{}) ([]byte, error)
+
+	largeAnyLimit int
+
+	closers []func() // 构建时打开的文件的关闭函数
+}
+
+// 同时输出的其他输出，level为空时与全局日志级别一致
+type teeOutput struct {
+	w     io.Writer
+	level string
+}
+
+type samplingOptions struct {
+	tick       time.Duration
+	first      int
+	thereafter int
+
+	onSampled func(zapcore.Entry)
+	onDropped func(zapcore.Entry)
+}
+
+func defaultOptions() *options {
+	return &options{
+		level:    "debug",
+		encoding: "console",
+	}
+}
+
+func (o *options) apply(opts ...Option) {
+	for _, opt := range opts {
+		opt(o)
+	}
+}
+
+// WithSave 输出日志到文件，文件只支持json格式，filename为空时默认为"out.log"
+func WithSave(filename string) Option {
+	return func(o *options) {
+		o.isSave = true
+		o.filename = filename
+	}
+}
+
+// WithSaveFailover 输出日志到文件，filenames按优先级排列，只写入第一个可用的文件，打开或写入失败时切换到下一个文件，
+// 并定期重试优先级更高的文件，与WithTee不同，每条日志只写入一个文件，不支持切割
+//
+//	eg: WithSaveFailover("/ssd/app.log", "/data/app.log")
+func WithSaveFailover(filenames ...string) Option {
+	return func(o *options) {
+		if len(filenames) == 0 {
+			return
+		}
+		o.isSave = true
+		o.filename = filenames[0]
+		o.failoverFiles = filenames[1:]
+	}
+}
+
+// WithSingleStream 输出到控台时所有级别的日志都输出到stdout，默认warn及以上级别的日志输出到stderr，
+// debug和info级别的日志输出到stdout
+func WithSingleStream() Option {
+	return func(o *options) {
+		o.singleStream = true
+	}
+}
+
+// WithLogLevel 设置输出日志级别 DEBUG, INFO, WARN, ERROR，默认DEBUG
+func WithLogLevel(level string) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// WithEncoding 设置控台输出格式 json、console(默认)或logfmt，logfmt格式为一行key=value，例如：ts=... level=info msg="..." key=value
+func WithEncoding(encoding string) Option {
+	return func(o *options) {
+		o.encoding = encoding
+	}
+}
+
+// WithTee 日志同时输出到w，输出格式和级别与主输出一致
+func WithTee(w io.Writer) Option {
+	return func(o *options) {
+		o.tees = append(o.tees, teeOutput{w: w})
+	}
+}
+
+// WithTeeLevel 日志同时输出到w，输出格式与主输出一致，只输出level及以上级别的日志，level为DEBUG, INFO, WARN, ERROR，
+// 例如主输出控台为DEBUG级别，同时输出到文件为INFO级别，level低于全局日志级别时以全局日志级别为准
+func WithTeeLevel(w io.Writer, level string) Option {
+	return func(o *options) {
+		o.tees = append(o.tees, teeOutput{w: w, level: level})
+	}
+}
+
+// WithWriteTimeout 每次写入输出的超时时间，慢输出(例如网络)写入超过d时不再等待，避免日志阻塞请求，
+// 等待上一次写入超时而没有写入的日志计入Stats的Dropped，对主输出和WithTee等其他输出都生效，
+// 每次写入会多一次内存复制和一个goroutine，输出不会很慢时不需要设置
+func WithWriteTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.writeTimeout = d
+	}
+}
+
+// WithFailover 日志同时输出到primary(例如远程日志服务)，primary写入失败时切换到secondary(例如本地文件)，
+// 并定期重试primary，两者都写入失败时缓存日志，恢复后补写，输出格式和级别与主输出一致
+func WithFailover(primary, secondary zapcore.WriteSyncer) Option {
+	return func(o *options) {
+		o.tees = append(o.tees, teeOutput{w: newFailoverWriteSyncer(primary, secondary)})
+	}
+}
+
+// WithFullCaller 显示完整的调用路径，例如："/go/src/project/pkg/sub/file.go:123"，默认只显示"sub/file.go:123"
+func WithFullCaller() Option {
+	return func(o *options) {
+		o.fullCaller = true
+	}
+}
+
+// WithCallerTrimPrefix 显示完整的调用路径并去掉前缀prefix，一般为项目根目录，例如："pkg/sub/file.go:123"
+func WithCallerTrimPrefix(prefix string) Option {
+	return func(o *options) {
+		o.fullCaller = true
+		o.callerTrimPrefix = prefix
+	}
+}
+
+// WithModuleName 调用位置显示为相对模块根目录的路径，module为go.mod中的模块路径，
+// 例如：WithModuleName("github.com/foo/bar")显示为"internal/svc/file.go:123"，与编译时的目录无关
+func WithModuleName(module string) Option {
+	return func(o *options) {
+		o.moduleName = module
+	}
+}
+
+// WithCallerFields 调用位置拆分为caller_file和caller_line两个字段输出，caller_line为整数，方便结构化查询
+func WithCallerFields() Option {
+	return func(o *options) {
+		o.callerFields = true
+	}
+}
+
+// WithEventLog 日志同时写入Windows事件日志，source为事件来源名称，
+// 日志级别映射为事件类型Info/Warning/Error，非windows系统初始化时返回错误
+func WithEventLog(source string) Option {
+	return func(o *options) {
+		o.eventLogSource = source
+	}
+}
+
+// WithJournald 日志同时通过journald原生协议写入systemd journal，PRIORITY由日志级别映射，
+// 日志字段的key转为大写作为journald字段，journald socket不可用时退化为输出到stderr，
+// 非linux系统初始化时返回错误
+func WithJournald() Option {
+	return func(o *options) {
+		o.journald = true
+	}
+}
+
+// WithCore 日志同时写入newCore在初始化时创建的core，用于接入依赖较多、放在子包中的输出，例如logger/otlp，
+// 日志级别由全局logger判断，core实现io.Closer时随logger一起关闭，
+// 设置后Ctx(ctx)输出的日志携带SkipType类型的ctx字段，core可以从中提取trace和span id，其他输出忽略该字段
+func WithCore(newCore func() (zapcore.Core, error)) Option {
+	return func(o *options) {
+		o.cores = append(o.cores, newCore)
+	}
+}
+
+// WithFallback 输出(例如已关闭的stdout)写入返回EBADF、EPIPE等错误时，改为输出到文件filename，
+// filename为空时丢弃日志，避免写入错误又输出到同一个已关闭的输出
+func WithFallback(filename string) Option {
+	return func(o *options) {
+		o.fallback = true
+		o.fallbackFilename = filename
+	}
+}
+
+// WithShortLevels 控台console格式使用单个字母表示日志级别，例如D/I/W/E，json格式和文件不受影响
+func WithShortLevels() Option {
+	return func(o *options) {
+		o.shortLevels = true
+	}
+}
+
+// WithSeverity 日志级别输出为syslog数字级别字段severity，Debug=7, Info=6, Warn=4, Error=3，
+// keepLevel为true时同时保留文字日志级别level
+func WithSeverity(keepLevel bool) Option {
+	return func(o *options) {
+		o.severity = true
+		o.keepLevel = keepLevel
+	}
+}
+
+// WithUTC 控台和文件的日志时间都使用UTC时区，默认使用本地时区
+func WithUTC() Option {
+	return func(o *options) {
+		o.utc = true
+	}
+}
+
+// WithColor 控台console格式的日志级别是否显示颜色，默认只有输出到终端时才显示颜色，
+// 输出被重定向到文件或管道时不显示，json格式和文件不受影响
+func WithColor(enable bool) Option {
+	return func(o *options) {
+		o.color = &enable
+	}
+}
+
+// WithLevelColors 显示颜色时各个日志级别的颜色，colors的值为ANSI颜色代码，例如："34"、"1;36"，
+// 未设置、无效或空的颜色使用默认颜色
+func WithLevelColors(colors map[zapcore.Level]string) Option {
+	return func(o *options) {
+		o.levelColors = colors
+	}
+}
+
+// WithFieldOrder 设置基础字段的输出顺序，可选值TimeField、LevelField、NameField、CallerField、MessageField，
+// 未指定的基础字段按默认顺序排在后面，例如：WithFieldOrder(LevelField, TimeField, MessageField)，
+// 主要用于控台console格式，json格式的字段顺序一般不被日志消费方保证
+func WithFieldOrder(fields ...string) Option {
+	return func(o *options) {
+		o.fieldOrder = fields
+	}
+}
+
+// WithSortedFields 控台console格式的字段(包括With添加的字段)按字段名排序输出，每行日志的字段顺序一致，方便查看，
+// json格式不受影响
+func WithSortedFields() Option {
+	return func(o *options) {
+		o.sortFields = true
+	}
+}
+
+// WithInitialFields 设置每条日志都携带的字段，例如service、env，在构建logger时设置
+func WithInitialFields(fields map[string]interface{}) Option {
+	return func(o *options) {
+		o.initialFields = fields
+	}
+}
+
+// WithMessagePrefix 每条日志的消息msg前面加上前缀prefix，例如WithMessagePrefix("[billing] ")，
+// 与zap的Named不同，Named设置的是logger字段
+func WithMessagePrefix(prefix string) Option {
+	return func(o *options) {
+		o.messagePrefix = prefix
+	}
+}
+
+// WithEpochField 每条日志添加字段key，值为日志时间的unix毫秒数，方便和监控指标关联，
+// 与日志时间使用同一个时钟(包括WithClock设置的时钟)
+func WithEpochField(key string) Option {
+	return func(o *options) {
+		o.epochField = key
+	}
+}
+
+// WithStacktraceTrim 去掉堆栈中zap和本包的帧，以及函数名以prefixes开头的帧，例如HTTP框架的帧，
+// 让堆栈更容易找到实际的调用位置
+//
+//	eg: WithStacktraceTrim("github.com/gin-gonic/gin", "net/http")
+func WithStacktraceTrim(prefixes ...string) Option {
+	return func(o *options) {
+		o.trimStack = true
+		o.stackTrim = prefixes
+	}
+}
+
+// WithSchemaVersion 每条日志都携带日志格式的版本字段schema_version，方便日志格式变化后区分字段含义，默认不携带
+func WithSchemaVersion(v string) Option {
+	return func(o *options) {
+		field := String("schema_version", v)
+		o.schemaField = &field
+	}
+}
+
+// WithProcessInfo 每条日志都携带进程id字段pid和主机名字段hostname，方便区分多进程部署时的日志来源，
+// 主机名获取失败时为unknown，默认不携带
+func WithProcessInfo() Option {
+	return func(o *options) {
+		o.processInfo = true
+	}
+}
+
+// WithSampling 对相同级别和消息的日志采样，每个tick时间内输出前first条，之后每thereafter条输出一条，
+// thereafter为0时丢弃之后的日志，tick必须大于0，例如：WithSampling(time.Second, 100, 100)
+func WithSampling(tick time.Duration, first int, thereafter int) Option {
+	return func(o *options) {
+		o.sampling = &samplingOptions{tick: tick, first: first, thereafter: thereafter}
+	}
+}
+
+// WithSamplingTick 设置采样周期d，每个周期开始时重新计数，未设置WithSampling时每个周期内输出前100条，
+// 之后每100条输出一条(与zap默认的采样参数一致)，测试时可以用ResetSampling手动清空计数
+func WithSamplingTick(d time.Duration) Option {
+	return func(o *options) {
+		if o.sampling == nil {
+			o.sampling = &samplingOptions{first: 100, thereafter: 100}
+		}
+		o.sampling.tick = d
+	}
+}
+
+// WithRateLimit 全局日志输出速率上限，每秒最多输出perSecond条日志，超出的日志被丢弃并计入Stats的Dropped，
+// 恢复输出时先输出一条日志汇总被丢弃的数量，与ErrorEvery不同，对所有调用位置一起限流，perSecond小于等于0时不限流
+func WithRateLimit(perSecond int) Option {
+	return func(o *options) {
+		o.rateLimit = perSecond
+	}
+}
+
+// WithRotationMode 设置日志文件的切割方式，RotationSize按大小切割，RotationTime按时间切割，
+// 文件名带日期，例如："app-2024-06-01.log"，只有输出到文件时有效
+func WithRotationMode(mode string) Option {
+	return func(o *options) {
+		o.rotationOptions().mode = mode
+	}
+}
+
+// WithRotationInterval 按时间切割的周期，默认为一天，没有设置WithRotationMode时按时间切割
+func WithRotationInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.rotationOptions().interval = d
+		o.inferRotationMode(RotationTime)
+	}
+}
+
+// WithRotationPattern 按时间切割时文件名中日期的格式，默认为"2006-01-02"，按小时切割时例如："2006-01-02-15"，
+// 没有设置WithRotationMode时按时间切割
+func WithRotationPattern(pattern string) Option {
+	return func(o *options) {
+		o.rotationOptions().pattern = pattern
+		o.inferRotationMode(RotationTime)
+	}
+}
+
+// WithMaxSize 按大小切割时单个文件的最大大小，单位MB，默认为100，没有设置WithRotationMode时按大小切割
+func WithMaxSize(mb int) Option {
+	return func(o *options) {
+		o.rotationOptions().maxSize = mb
+		o.inferRotationMode(RotationSize)
+	}
+}
+
+// WithAudit 设置审计日志文件，Audit输出的审计日志只追加写入该文件，与应用日志的输出和级别无关
+func WithAudit(filename string) Option {
+	return func(o *options) {
+		o.auditFilename = filename
+	}
+}
+
+// WithAnyEncoder 设置AnyWith字段的序列化函数，返回值必须是json，例如使用protojson序列化proto消息，
+// 避免反射并遵循proto的json字段选项
+//
+//	eg: WithAnyEncoder(func(v interface{}) ([]byte, error) { return protojson.Marshal(v.(proto.Message)) })
+func WithAnyEncoder(fn func(interface{}) ([]byte, error)) Option {
+	return func(o *options) {
+		o.anyEncoder = fn
+	}
+}
+
+// WithLargeAnyWarning 用于开发环境，Any的值序列化为json后超过maxBytes字节时，每个调用位置输出一次warn级别日志，
+// 提醒不要输出过大的对象，检查需要额外序列化一次，生产环境不要设置
+func WithLargeAnyWarning(maxBytes int) Option {
+	return func(o *options) {
+		o.largeAnyLimit = maxBytes
+	}
+}
+
+// WithClock 设置日志使用的时钟，影响日志时间和按时间切割文件，一般用于测试
+func WithClock(clock zapcore.Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// 没有设置切割方式时根据切割参数确定切割方式，WithRotationMode设置的切割方式优先
+func (o *options) inferRotationMode(mode string) {
+	if o.rotation.mode == "" {
+		o.rotation.mode = mode
+	}
+}
+
+func (o *options) rotationOptions() *rotationOptions {
+	if o.rotation == nil {
+		o.rotation = &rotationOptions{}
+	}
+	return o.rotation
+}
+
+func (o *options) getClock() zapcore.Clock {
+	if o.clock != nil {
+		return o.clock
+	}
+	return zapcore.DefaultClock
+}
+
+// WithSamplingKey 使用Ctx(ctx)输出的日志按context中ctxKey的值(例如请求id)分别采样，
+// 一个请求的大量日志不会挤占其他请求的采样额度，需要同时设置WithSampling
+//
+//	eg: Init(WithSampling(time.Second, 10, 0), WithSamplingKey("X-B3-TraceId"))
+func WithSamplingKey(ctxKey string) Option {
+	return func(o *options) {
+		o.samplingKey = ctxKey
+	}
+}
+
+// WithSamplingHooks 设置采样的回调函数，日志被采样输出时调用onSampled，被丢弃时调用onDropped，
+// 例如按日志级别统计被丢弃的日志数，只有设置了WithSampling或WithSamplingTick时有效，不需要的回调可以为nil
+func WithSamplingHooks(onSampled, onDropped func(zapcore.Entry)) Option {
+	return func(o *options) {
+		o.onSampled = onSampled
+		o.onDropped = onDropped
+	}
+}
+
+// 输出到控台时是否把warn及以上级别的日志输出到stderr
+func (o *options) splitStreams() bool {
+	return !o.isSave && !o.singleStream
+}
+
+// 是否以console格式输出到控台
+func (o *options) isConsole() bool {
+	return !o.isSave && o.encoding != "json" && o.encoding != "logfmt"
+}
+
+// WithCompactInts Int64s、Uint64s、Int32s最多输出max个元素，超过的部分用"...(N more)"表示，限制日志长度
+func WithCompactInts(max int) Option {
+	return func(o *options) {
+		o.compactInts = max
+	}
+}
+
+// 输出到stdout时是否显示颜色，未设置时根据stdout是否为终端判断
+func (o *options) colorEnabled() bool {
+	return colorOutput(o.color, os.Stdout)
+}
+
+// 根据选项修改编码配置
+func (o *options) setEncoderConfig(encoderConfig *zapcore.EncoderConfig) {
+	o.setLevelEncoder(encoderConfig, o.isConsole(), constColor(o.colorEnabled()))
+	if o.utc {
+		encoderConfig.EncodeTime = utcTimeEncoder(encoderConfig.EncodeTime)
+	}
+	if o.fullCaller {
+		encoderConfig.EncodeCaller = zapcore.FullCallerEncoder
+		if o.callerTrimPrefix != "" {
+			encoderConfig.EncodeCaller = trimCallerEncoder(o.callerTrimPrefix)
+		}
+	}
+	if o.moduleName != "" {
+		encoderConfig.EncodeCaller = moduleCallerEncoder(o.moduleName)
+	}
+}
+
+// 根据选项设置日志级别的编码，console为是否以console格式输出到控台
+func (o *options) setLevelEncoder(encoderConfig *zapcore.EncoderConfig, console bool, color func() bool) {
+	encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	if o.shortLevels && console {
+		encoderConfig.EncodeLevel = shortLevelEncoder
+	}
+	if console {
+		encoderConfig.EncodeLevel = switchColorLevelEncoder(encoderConfig.EncodeLevel, o.levelColors, color)
+	}
+	if o.severity && !o.keepLevel {
+		encoderConfig.LevelKey = "severity"
+		encoderConfig.EncodeLevel = severityLevelEncoder
+	}
+}
+
+// 根据配置和选项构建logger
+func (o *options) build(config zap.Config) (*zap.Logger, error) {
+	sink, errSink, err := o.openSinks(config)
+	if err != nil {
+		return nil, err
+	}
+
+	out := newSwapWriteSyncer(sink)
+	out.setColor(o.color, os.Stdout)
+	o.state.mainOutput = out
+	sink = o.withTimeout(countingWriteSyncer{out})
+	var highSink zapcore.WriteSyncer // 输出到控台时warn及以上级别的输出
+	if o.splitStreams() {
+		stderr, err := o.open("stderr")
+		if err != nil {
+			return nil, err
+		}
+		errOut := newSwapWriteSyncer(stderr)
+		errOut.setColor(o.color, os.Stderr)
+		o.state.stderrOutput = errOut
+		highSink = o.withTimeout(countingWriteSyncer{errOut})
+	}
+	if o.fallback {
+		secondary := zapcore.AddSync(io.Discard)
+		if o.fallbackFilename != "" {
+			secondary, err = o.open(o.fallbackFilename)
+			if err != nil {
+				return nil, err
+			}
+		}
+		sink = newFallbackWriteSyncer(sink, secondary)
+		if highSink != nil {
+			highSink = newFallbackWriteSyncer(highSink, secondary)
+		}
+		errSink = newFallbackWriteSyncer(errSink, secondary)
+	}
+
+	core, err := o.buildCore(config, sink, highSink, out.colored, o.state.stderrOutput.colored)
+	if err != nil {
+		return nil, err
+	}
+
+	audit, err := o.buildAuditLogger()
+	if err != nil {
+		return nil, err
+	}
+	o.state.audit = audit
+
+	zapOpts := []zap.Option{zap.ErrorOutput(errSink), zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel), zap.WithFatalHook(fatalHook{}), zap.WithClock(o.getClock())}
+	if len(config.InitialFields) > 0 {
+		keys := make([]string, 0, len(config.InitialFields))
+		for k := range config.InitialFields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		fields := make([]Field, 0, len(keys))
+		for _, k := range keys {
+			fields = append(fields, Any(k, config.InitialFields[k]))
+		}
+		zapOpts = append(zapOpts, zap.Fields(fields...))
+	}
+	if o.schemaField != nil {
+		zapOpts = append(zapOpts, zap.Fields(*o.schemaField))
+	}
+	if o.processInfo {
+		zapOpts = append(zapOpts, zap.Fields(processInfoFields()...))
+	}
+
+	return zap.New(core, zapOpts...), nil
+}
+
+// 打开日志输出和错误输出，输出到文件并设置了切割方式时两者共用切割文件
+func (o *options) openSinks(config zap.Config) (zapcore.WriteSyncer, zapcore.WriteSyncer, error) {
+	if o.isSave && o.rotation != nil && len(config.OutputPaths) > 0 {
+		w, err := newRotateWriter(config.OutputPaths[0], *o.rotation, o.getClock())
+		if err != nil {
+			return nil, nil, err
+		}
+		o.closers = append(o.closers, func() { _ = w.Close() })
+		return w, w, nil
+	}
+
+	if o.isSave && len(o.failoverFiles) > 0 && len(config.OutputPaths) > 0 {
+		ws, err := o.openFailover(append([]string{config.OutputPaths[0]}, o.failoverFiles...))
+		if err != nil {
+			return nil, nil, err
+		}
+		return ws, ws, nil
+	}
+
+	sink, err := o.open(config.OutputPaths...)
+	if err != nil {
+		return nil, nil, err
+	}
+	errSink, err := o.open(config.ErrorOutputPaths...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return sink, errSink, nil
+}
+
+// 设置了写入超时时包装输出
+func (o *options) withTimeout(ws zapcore.WriteSyncer) zapcore.WriteSyncer {
+	if o.writeTimeout > 0 {
+		return newTimeoutWriteSyncer(ws, o.writeTimeout)
+	}
+	return ws
+}
+
+// 打开输出并记录关闭函数，Close时关闭
+func (o *options) open(paths ...string) (zapcore.WriteSyncer, error) {
+	ws, closeFn, err := zap.Open(paths...)
+	if err != nil {
+		return nil, err
+	}
+	o.closers = append(o.closers, closeFn)
+	return ws, nil
+}
+
+// 按优先级打开文件，打开失败的文件在切换回该文件时重新打开，所有文件都打开失败时返回第一个错误
+func (o *options) openFailover(paths []string) (zapcore.WriteSyncer, error) {
+	sinks := make([]zapcore.WriteSyncer, 0, len(paths))
+	var firstErr error
+	opened := 0
+	for _, path := range paths {
+		ws, err := o.open(path)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			r := &reopenWriteSyncer{path: path}
+			o.closers = append(o.closers, r.close)
+			sinks = append(sinks, r)
+			continue
+		}
+		sinks = append(sinks, ws)
+		opened++
+	}
+	if opened == 0 {
+		return nil, firstErr
+	}
+
+	ws := sinks[len(sinks)-1]
+	for i := len(sinks) - 2; i >= 0; i-- {
+		ws = newFailoverWriteSyncer(sinks[i], ws)
+	}
+	return ws, nil
+}
+
+// core持有需要释放的资源(事件日志句柄、连接等)时记录关闭函数，Close或重新初始化时关闭
+func (o *options) closeCore(core zapcore.Core) {
+	if c, ok := core.(io.Closer); ok {
+		o.closers = append(o.closers, func() { _ = c.Close() })
+	}
+}
+
+// 关闭构建时打开的文件，后打开的先关闭，例如先停止定时汇总再关闭输出文件
+func (o *options) close() {
+	for i := len(o.closers) - 1; i >= 0; i-- {
+		o.closers[i]()
+	}
+}
+
+// 根据选项构建core，从内到外依次为：输出、调用位置字段、采样、统计、日志级别判断，
+// sinkColor和highColor返回对应的输出当前是否显示颜色
+func (o *options) buildCore(config zap.Config, sink, highSink zapcore.WriteSyncer, sinkColor, highColor func() bool) (zapcore.Core, error) {
+	// 日志级别统一由最外层的rootCore判断
+	allLevels := zapcore.DebugLevel
+
+	encoder := o.newEncoder(config, sinkColor)
+	plainEncoder := o.newEncoder(config, constColor(false)) // 事件日志、journald等不显示颜色
+	cores := []zapcore.Core{zapcore.NewCore(encoder, sink, allLevels)}
+	if highSink != nil {
+		cores = []zapcore.Core{
+			zapcore.NewCore(encoder, sink, zap.LevelEnablerFunc(func(l zapcore.Level) bool { return l < zapcore.WarnLevel })),
+			zapcore.NewCore(o.newEncoder(config, highColor), highSink, zapcore.WarnLevel),
+		}
+	}
+	for _, t := range o.tees {
+		level := allLevels
+		if t.level != "" {
+			var err error
+			if level, err = zapcore.ParseLevel(strings.ToLower(t.level)); err != nil {
+				return nil, err
+			}
+		}
+		teeEncoder := o.newEncoder(config, constColor(colorOutput(o.color, t.w)))
+		cores = append(cores, zapcore.NewCore(teeEncoder, o.withTimeout(countingWriteSyncer{zapcore.AddSync(t.w)}), level))
+	}
+	if o.eventLogSource != "" {
+		core, err := newEventLogCore(o.eventLogSource, plainEncoder.Clone(), allLevels)
+		if err != nil {
+			return nil, err
+		}
+		o.closeCore(core)
+		cores = append(cores, core)
+	}
+	if o.journald {
+		core, err := newJournaldCore(plainEncoder.Clone(), allLevels)
+		if err != nil {
+			return nil, err
+		}
+		o.closeCore(core)
+		cores = append(cores, core)
+	}
+	for _, newCore := range o.cores {
+		core, err := newCore()
+		if err != nil {
+			return nil, err
+		}
+		o.closeCore(core)
+		cores = append(cores, core)
+	}
+	o.state.ctxField = len(o.cores) > 0
+	core := zapcore.NewTee(cores...)
+
+	if o.severity && o.keepLevel {
+		core = newSeverityCore(core)
+	}
+	core = &buildInfoCore{Core: core}
+	if o.messagePrefix != "" {
+		prefix := o.messagePrefix
+		core = &interceptCore{Core: core, fn: func(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+			ent.Message = prefix + ent.Message
+			return ent, fields, true
+		}}
+	}
+	if o.epochField != "" {
+		key := o.epochField
+		core = &interceptCore{Core: core, fn: func(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+			return ent, append(fields[:len(fields):len(fields)], Int64(key, ent.Time.UnixMilli())), true
+		}}
+	}
+	if o.trimStack {
+		core = newStackTrimCore(core, o.stackTrim)
+	}
+	if o.callerFields {
+		core = newCallerFieldsCore(core, o.fullCaller, o.callerTrimPrefix, o.moduleName)
+	}
+
+	if s := o.sampling; s != nil {
+		if s.tick <= 0 {
+			return nil, errors.New("sampling tick must be greater than 0")
+		}
+		opts := *s
+		opts.onSampled, opts.onDropped = o.onSampled, o.onDropped
+		state := newSamplingState(opts)
+		core = &samplingCore{Core: core, state: state}
+		o.state.sampling = state
+		o.state.samplingKey = o.samplingKey
+	}
+
+	if o.rateLimit > 0 {
+		limiter := newRateLimiter(o.rateLimit, core, config.Level, o.getClock())
+		limiter.start()
+		o.closers = append(o.closers, limiter.close)
+		core = &rateLimitCore{Core: core, limiter: limiter}
+	}
+
+	o.state.compactInts = int64(o.compactInts)
+	o.state.largeAnyLimit = int64(o.largeAnyLimit)
+	if o.anyEncoder != nil {
+		o.state.anyEncoder = &o.anyEncoder
+	}
+
+	core = zapcore.RegisterHooks(core, countEntry)
+	if o.sortFields && o.isConsole() {
+		core = &sortedFieldsCore{Core: core}
+	}
+	return &rootCore{Core: core, enab: config.Level}, nil
+}
+
+// 新建可以用SetEncoding切换json和console格式的encoder，color返回输出当前是否显示颜色，
+// 同一个logger的encoder共用一个格式开关
+func (o *options) newEncoder(config zap.Config, color func() bool) zapcore.Encoder {
+	if config.Encoding == "logfmt" { // logfmt格式不支持切换格式和调整基础字段顺序
+		encoderConfig := config.EncoderConfig
+		o.setLevelEncoder(&encoderConfig, false, nil)
+		return newLogfmtEncoder(encoderConfig)
+	}
+	if o.state.encoding == nil {
+		o.state.encoding = &encodingSwitch{}
+		o.state.encoding.json.Store(config.Encoding == "json")
+	}
+	return &switchEncoder{
+		mode:    o.state.encoding,
+		json:    o.newEncoderFor("json", config.EncoderConfig, color),
+		console: o.newEncoderFor("console", config.EncoderConfig, color),
+	}
+}
+
+func (o *options) newEncoderFor(encoding string, encoderConfig zapcore.EncoderConfig, color func() bool) zapcore.Encoder {
+	o.setLevelEncoder(&encoderConfig, encoding == "console" && !o.isSave, color)
+	if len(o.fieldOrder) > 0 {
+		return newOrderedEncoder(encoding, encoderConfig, o.fieldOrder)
+	}
+	return newEncoder(encoding, encoderConfig)
+}
+
+func newEncoder(encoding string, encoderConfig zapcore.EncoderConfig) zapcore.Encoder {
+	if encoding == "json" {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
+
+// 去掉调用路径前缀
+func trimCallerEncoder(prefix string) zapcore.CallerEncoder {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	return func(caller zapcore.EntryCaller, enc zapcore.PrimitiveArrayEncoder) {
+		if !caller.Defined {
+			enc.AppendString("undefined")
+			return
+		}
+		enc.AppendString(strings.TrimPrefix(caller.FullPath(), prefix))
+	}
+}
+
+// 转换为UTC时区后再编码时间
+func utcTimeEncoder(base zapcore.TimeEncoder) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		base(t.UTC(), enc)
+	}
+}
+
+// 单个字母表示日志级别
+func shortLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	switch level {
+	case zapcore.DebugLevel:
+		enc.AppendString("D")
+	case zapcore.InfoLevel:
+		enc.AppendString("I")
+	case zapcore.WarnLevel:
+		enc.AppendString("W")
+	case zapcore.ErrorLevel:
+		enc.AppendString("E")
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		enc.AppendString("P")
+	case zapcore.FatalLevel:
+		enc.AppendString("F")
+	default:
+		enc.AppendString(level.CapitalString())
+	}
+}
@@ -0,0 +1,250 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestWithFullCaller(t *testing.T) {
+	filename := initTestLogger(t, WithFullCaller())
+	_, file, line, _ := runtime.Caller(0)
+	Info("full caller")
+
+	entry := findLogLine(readLogLines(t, filename), "full caller")
+	if want := fmt.Sprintf("%s:%d", file, line+1); entry["caller"] != want {
+		t.Errorf("expected caller %q, got %v", want, entry["caller"])
+	}
+}
+
+func TestWithCallerTrimPrefix(t *testing.T) {
+	_, file, _, _ := runtime.Caller(0)
+	filename := initTestLogger(t, WithCallerTrimPrefix(filepath.Dir(file)))
+	_, _, line, _ := runtime.Caller(0)
+	Info("trim caller")
+
+	entry := findLogLine(readLogLines(t, filename), "trim caller")
+	if want := fmt.Sprintf("options_test.go:%d", line+1); entry["caller"] != want {
+		t.Errorf("expected caller %q, got %v", want, entry["caller"])
+	}
+}
+
+func TestWithInitialFields(t *testing.T) {
+	filename := initTestLogger(t, WithInitialFields(map[string]interface{}{"service": "api", "env": "prod"}))
+	Info("initial fields")
+
+	entry := findLogLine(readLogLines(t, filename), "initial fields")
+	if entry["service"] != "api" || entry["env"] != "prod" {
+		t.Errorf("expected initial fields, got %v", entry)
+	}
+}
+
+func TestWithSchemaVersion(t *testing.T) {
+	filename := initTestLogger(t, WithSchemaVersion("v2"))
+	Info("schema version")
+
+	entry := findLogLine(readLogLines(t, filename), "schema version")
+	if entry["schema_version"] != "v2" {
+		t.Errorf("expected schema_version field, got %v", entry)
+	}
+
+	filename = initTestLogger(t)
+	Info("no schema version")
+	entry = findLogLine(readLogLines(t, filename), "no schema version")
+	if _, ok := entry["schema_version"]; ok {
+		t.Errorf("expected no schema_version field by default, got %v", entry)
+	}
+}
+
+func TestWithProcessInfo(t *testing.T) {
+	filename := initTestLogger(t, WithProcessInfo())
+	Info("process info")
+
+	entry := findLogLine(readLogLines(t, filename), "process info")
+	hostname, _ := os.Hostname()
+	if entry["pid"] != float64(os.Getpid()) || entry["hostname"] != hostname {
+		t.Errorf("expected pid and hostname fields, got %v", entry)
+	}
+
+	filename = initTestLogger(t)
+	Info("no process info")
+	entry = findLogLine(readLogLines(t, filename), "no process info")
+	if _, ok := entry["pid"]; ok {
+		t.Errorf("expected no pid field by default, got %v", entry)
+	}
+}
+
+func TestWithMessagePrefix(t *testing.T) {
+	filename := initTestLogger(t, WithMessagePrefix("[billing] "))
+	WithFields().Named("payment").Info("charge succeeded")
+
+	entry := findLogLine(readLogLines(t, filename), "[billing] charge succeeded")
+	if entry == nil {
+		t.Fatal("expected message with prefix")
+	}
+	if entry["logger"] != "payment" {
+		t.Errorf("expected logger name to be unchanged, got %v", entry["logger"])
+	}
+}
+
+func TestWithEpochField(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 123e6, time.UTC)
+	filename := initTestLogger(t, WithEpochField("ts_ms"), WithClock(&mockClock{now: now}))
+	Info("epoch")
+
+	entry := findLogLine(readLogLines(t, filename), "epoch")
+	if entry["ts_ms"] != float64(now.UnixMilli()) {
+		t.Errorf("expected ts_ms=%d, got %v", now.UnixMilli(), entry["ts_ms"])
+	}
+}
+
+func TestWithSampling(t *testing.T) {
+	if err := Init(WithSampling(0, 1, 0)); err == nil {
+		t.Error("expected error for zero sampling tick")
+	}
+
+	filename := initTestLogger(t, WithSampling(100*time.Millisecond, 1, 0))
+	for i := 0; i < 3; i++ {
+		Info("sampled")
+	}
+	time.Sleep(150 * time.Millisecond) // 超过tick后重新计数
+	for i := 0; i < 3; i++ {
+		Info("sampled")
+	}
+
+	count := 0
+	for _, line := range readLogLines(t, filename) {
+		if line["msg"] == "sampled" {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected 2 sampled entries, got %d", count)
+	}
+}
+
+func TestWithSamplingTick(t *testing.T) {
+	filename := initTestLogger(t, WithSamplingTick(time.Minute))
+	for i := 0; i < 250; i++ {
+		Info("sampled tick")
+	}
+
+	count := 0
+	for _, line := range readLogLines(t, filename) {
+		if line["msg"] == "sampled tick" {
+			count++
+		}
+	}
+	if count != 101 {
+		t.Errorf("expected 101 sampled entries, got %d", count)
+	}
+}
+
+func TestWithUTC(t *testing.T) {
+	clock := &mockClock{now: time.Date(2024, 6, 1, 8, 0, 0, 0, time.FixedZone("CST", 8*3600))}
+	filename := initTestLogger(t, WithUTC(), WithClock(clock))
+	Info("utc time")
+
+	entry := findLogLine(readLogLines(t, filename), "utc time")
+	if entry["ts"] != "2024-06-01T00:00:00.000Z" {
+		t.Errorf("expected UTC timestamp, got %v", entry["ts"])
+	}
+}
+
+func TestWithTeeLevel(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "info.log")
+	file, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if err := Init(WithLogLevel("debug"), WithColor(false), WithTeeLevel(file, "info")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resetLogger(t) })
+	console := &bytes.Buffer{}
+	SetOutput(console)
+	Debug("debug entry")
+	Info("info entry")
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "debug entry") || !strings.Contains(string(data), "info entry") {
+		t.Errorf("expected only info entry in file, got %q", data)
+	}
+	if !strings.Contains(console.String(), "debug entry") || !strings.Contains(console.String(), "info entry") {
+		t.Errorf("expected debug and info entries on console, got %q", console.String())
+	}
+
+	if err := Init(WithTeeLevel(file, "verbose")); err == nil {
+		t.Error("expected error for invalid tee level")
+	}
+}
+
+// 记录关闭状态的扩展core
+type closingCore struct {
+	zapcore.Core
+	closed *bool
+}
+
+func (c closingCore) Close() error {
+	*c.closed = true
+	return nil
+}
+
+func TestWithCore(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	closed := false
+	initTestLogger(t, WithLogLevel("info"), WithCore(func() (zapcore.Core, error) {
+		return closingCore{Core: core, closed: &closed}, nil
+	}))
+
+	Debug("core debug")
+	Ctx(context.WithValue(context.Background(), "X-B3-TraceId", "t-1")).Info("core info")
+
+	entries := logs.FilterMessage("core info").All()
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry in extra core, got %d", len(entries))
+	}
+	if logs.FilterMessage("core debug").Len() != 0 {
+		t.Error("expected debug entry to be filtered by the global level")
+	}
+	var ctxField bool
+	for _, f := range entries[0].Context {
+		if _, ok := f.Interface.(context.Context); ok && f.Type == zapcore.SkipType {
+			ctxField = true
+		}
+	}
+	if !ctxField {
+		t.Errorf("expected ctx field for the extra core, got %v", entries[0].Context)
+	}
+	if EffectiveConfig().CoreCount != 1 {
+		t.Errorf("expected core count 1, got %d", EffectiveConfig().CoreCount)
+	}
+
+	if err := Close(); err != nil {
+		t.Fatal(err)
+	}
+	if !closed {
+		t.Error("expected extra core to be closed with the logger")
+	}
+}
+
+func TestWithCoreError(t *testing.T) {
+	if err := Init(WithCore(func() (zapcore.Core, error) { return nil, errors.New("unavailable") })); err == nil {
+		t.Error("expected error from extra core")
+	}
+}
@@ -0,0 +1,71 @@
+// Package otlp 把日志以OTel LogRecord发送到OTLP接收端，依赖OTel SDK和grpc，
+// 放在子包中，不使用OTLP的程序不需要引入这些依赖
+package otlp
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/zhufuyi/logger"
+	"go.opentelemetry.io/contrib/bridges/otelzap"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	scopeName       = "github.com/zhufuyi/logger"
+	shutdownTimeout = 5 * time.Second
+)
+
+// WithOTLP 日志同时以OTel LogRecord发送到OTLP接收端，endpoint为grpc地址，例如："localhost:4317"，
+// 或者url，例如："http://localhost:4317"，日志级别映射为severity，字段映射为attributes，
+// 使用logger.Ctx(ctx)输出的日志会关联context中的trace和span id，批量发送和发送失败的处理使用OTel SDK的默认配置
+//
+//	eg: logger.Init(logger.WithLogLevel("info"), otlp.WithOTLP("localhost:4317"))
+func WithOTLP(endpoint string) logger.Option {
+	return logger.WithCore(func() (zapcore.Core, error) {
+		return newCore(endpoint)
+	})
+}
+
+// 发送OTel LogRecord的core，关闭logger时关闭provider
+type otlpCore struct {
+	*otelzap.Core
+	provider *sdklog.LoggerProvider
+}
+
+func newCore(endpoint string) (*otlpCore, error) {
+	var opts []otlploggrpc.Option
+	if strings.Contains(endpoint, "://") {
+		opts = append(opts, otlploggrpc.WithEndpointURL(endpoint))
+	} else {
+		opts = append(opts, otlploggrpc.WithEndpoint(endpoint))
+	}
+	exporter, err := otlploggrpc.New(context.Background(), opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)))
+	return &otlpCore{
+		Core:     otelzap.NewCore(scopeName, otelzap.WithLoggerProvider(provider)),
+		provider: provider,
+	}, nil
+}
+
+func (c *otlpCore) With(fields []zapcore.Field) zapcore.Core {
+	return &otlpCore{Core: c.Core.With(fields).(*otelzap.Core), provider: c.provider}
+}
+
+func (c *otlpCore) Sync() error {
+	return c.provider.ForceFlush(context.Background())
+}
+
+// 发送缓存的日志并关闭provider，接收端不可用时最多等待shutdownTimeout
+func (c *otlpCore) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return c.provider.Shutdown(ctx)
+}
@@ -0,0 +1,135 @@
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/zhufuyi/logger"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+)
+
+// 在进程内接收OTLP日志的collector
+type otlpCollector struct {
+	collogspb.UnimplementedLogsServiceServer
+
+	mu      sync.Mutex
+	records []*logspb.LogRecord
+}
+
+func (c *otlpCollector) Export(_ context.Context, req *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, rl := range req.GetResourceLogs() {
+		for _, sl := range rl.GetScopeLogs() {
+			c.records = append(c.records, sl.GetLogRecords()...)
+		}
+	}
+	return &collogspb.ExportLogsServiceResponse{}, nil
+}
+
+func (c *otlpCollector) find(body string) *logspb.LogRecord {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, r := range c.records {
+		if r.GetBody().GetStringValue() == body {
+			return r
+		}
+	}
+	return nil
+}
+
+// 启动collector，返回endpoint
+func startOTLPCollector(t *testing.T) (*otlpCollector, string) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collector := &otlpCollector{}
+	server := grpc.NewServer()
+	collogspb.RegisterLogsServiceServer(server, collector)
+	go func() { _ = server.Serve(lis) }()
+	t.Cleanup(server.Stop)
+	return collector, "http://" + lis.Addr().String()
+}
+
+func TestWithOTLP(t *testing.T) {
+	collector, endpoint := startOTLPCollector(t)
+	if err := logger.Init(logger.WithSave(filepath.Join(t.TempDir(), "out.log")), logger.WithLogLevel("info"), WithOTLP(endpoint)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1, 2, 3},
+		SpanID:     trace.SpanID{4, 5, 6},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	logger.Debug("otlp debug")
+	logger.Info("otlp info", logger.String("user", "foo"))
+	logger.Ctx(ctx).Error("otlp error")
+	if err := logger.Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	record := collector.find("otlp info")
+	if record == nil {
+		t.Fatal("expected info record in collector")
+	}
+	if record.GetSeverityText() != "info" || record.GetSeverityNumber() != logspb.SeverityNumber_SEVERITY_NUMBER_INFO {
+		t.Errorf("unexpected severity %s %s", record.GetSeverityText(), record.GetSeverityNumber())
+	}
+	var user string
+	for _, attr := range record.GetAttributes() {
+		if attr.GetKey() == "user" {
+			user = attr.GetValue().GetStringValue()
+		}
+	}
+	if user != "foo" {
+		t.Errorf("expected user attribute, got %v", record.GetAttributes())
+	}
+	if collector.find("otlp debug") != nil {
+		t.Error("expected debug record to be filtered by level")
+	}
+
+	// Ctx(ctx)输出的日志关联context中的trace和span id
+	record = collector.find("otlp error")
+	if record == nil {
+		t.Fatal("expected error record in collector")
+	}
+	if id := spanCtx.TraceID(); !bytes.Equal(record.GetTraceId(), id[:]) {
+		t.Errorf("expected trace id %s, got %x", spanCtx.TraceID(), record.GetTraceId())
+	}
+	if id := spanCtx.SpanID(); !bytes.Equal(record.GetSpanId(), id[:]) {
+		t.Errorf("expected span id %s, got %x", spanCtx.SpanID(), record.GetSpanId())
+	}
+}
+
+func TestCoreClose(t *testing.T) {
+	collector, endpoint := startOTLPCollector(t)
+	core, err := newCore(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ent := zapcore.Entry{Level: zapcore.InfoLevel, Message: "before close"}
+	if ce := core.Check(ent, nil); ce != nil {
+		ce.Write()
+	}
+	if err := core.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// 关闭时发送缓存的日志
+	if collector.find("before close") == nil {
+		t.Error("expected buffered record to be exported on close")
+	}
+}
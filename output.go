@@ -0,0 +1,72 @@
+package logger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 当前logger的主输出
+var mainOutput atomic.Pointer[swapWriteSyncer]
+
+// 输出到控台时warn及以上级别日志的输出stderr，使用WithSingleStream或输出到文件时为nil
+var stderrOutput atomic.Pointer[swapWriteSyncer]
+
+// SetOutput 把当前logger的主输出(包括输出到stderr的warn及以上级别日志)重定向到w，日志级别和输出格式不变，
+// w不需要并发安全，例如在解析命令行参数后决定输出位置，原输出不会被关闭
+func SetOutput(w io.Writer) {
+	loadLogger()
+	ws := zapcore.Lock(zapcore.AddSync(w))
+	if out := mainOutput.Load(); out != nil {
+		out.swap(ws, w)
+	}
+	if out := stderrOutput.Load(); out != nil {
+		out.swap(ws, w)
+	}
+}
+
+// 可以并发安全替换的输出，替换时重新判断是否显示颜色
+type swapWriteSyncer struct {
+	mu sync.RWMutex
+	ws zapcore.WriteSyncer
+
+	color      atomic.Bool
+	forceColor *bool // WithColor设置的值
+}
+
+func newSwapWriteSyncer(ws zapcore.WriteSyncer) *swapWriteSyncer {
+	return &swapWriteSyncer{ws: ws}
+}
+
+func (s *swapWriteSyncer) Write(p []byte) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ws.Write(p)
+}
+
+func (s *swapWriteSyncer) Sync() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ws.Sync()
+}
+
+// 替换输出为ws，w为ws对应的原始输出，用于判断是否为终端
+func (s *swapWriteSyncer) swap(ws zapcore.WriteSyncer, w io.Writer) {
+	s.mu.Lock()
+	s.ws = ws
+	s.color.Store(colorOutput(s.forceColor, w))
+	s.mu.Unlock()
+}
+
+// 设置是否显示颜色，w为当前的原始输出
+func (s *swapWriteSyncer) setColor(force *bool, w io.Writer) {
+	s.forceColor = force
+	s.color.Store(colorOutput(force, w))
+}
+
+// 当前输出是否显示颜色，没有输出时返回false
+func (s *swapWriteSyncer) colored() bool {
+	return s != nil && s.color.Load()
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetOutput(t *testing.T) {
+	filename := initTestLogger(t, WithLogLevel("info"))
+	Info("before redirect")
+
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	Info("after redirect")
+	Debug("debug after redirect")
+
+	if findLogLine(readLogLines(t, filename), "after redirect") != nil {
+		t.Error("expected entry after redirect not in the original output")
+	}
+	if findLogLine(readLogLines(t, filename), "before redirect") == nil {
+		t.Error("expected entry before redirect in the original output")
+	}
+	if !strings.Contains(buf.String(), `"msg":"after redirect"`) {
+		t.Errorf("expected json entry in new output, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "debug after redirect") {
+		t.Error("expected level to be unchanged")
+	}
+}
+
+func TestSetOutputAfterFailedInit(t *testing.T) {
+	filename := initTestLogger(t)
+
+	// 初始化失败时保留原来的logger和输出
+	if err := Init(WithTeeLevel(&bytes.Buffer{}, "bogus")); err == nil {
+		t.Fatal("expected error for invalid tee level")
+	}
+	buf := &bytes.Buffer{}
+	SetOutput(buf)
+	Info("after failed init")
+
+	if findLogLine(readLogLines(t, filename), "after failed init") != nil {
+		t.Error("expected entry to be redirected away from the original output")
+	}
+	if !strings.Contains(buf.String(), "after failed init") {
+		t.Errorf("expected entry in new output, got %q", buf.String())
+	}
+}
+
+// 把os.Stdout和os.Stderr替换为临时文件后初始化logger，返回读取两个文件内容的函数
+func captureStdStreams(t *testing.T, opts ...Option) func() (stdout, stderr string) {
+	dir := t.TempDir()
+	stdout, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldStdout, oldStderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = stdout, stderr
+	err = Init(append([]Option{WithEncoding("json")}, opts...)...)
+	os.Stdout, os.Stderr = oldStdout, oldStderr
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		resetLogger(t)
+		stdout.Close()
+		stderr.Close()
+	})
+
+	return func() (string, string) {
+		out, _ := os.ReadFile(stdout.Name())
+		errOut, _ := os.ReadFile(stderr.Name())
+		return string(out), string(errOut)
+	}
+}
+
+func TestConsoleStreams(t *testing.T) {
+	read := captureStdStreams(t)
+	Info("info entry")
+	Warn("warn entry")
+	Error("error entry")
+
+	stdout, stderr := read()
+	if !strings.Contains(stdout, "info entry") || strings.Contains(stdout, "warn entry") || strings.Contains(stdout, "error entry") {
+		t.Errorf("expected only debug and info entries in stdout, got %q", stdout)
+	}
+	if !strings.Contains(stderr, "warn entry") || !strings.Contains(stderr, "error entry") || strings.Contains(stderr, "info entry") {
+		t.Errorf("expected warn and error entries in stderr, got %q", stderr)
+	}
+}
+
+func TestWithSingleStream(t *testing.T) {
+	read := captureStdStreams(t, WithSingleStream())
+	Info("info entry")
+	Error("error entry")
+
+	stdout, stderr := read()
+	if !strings.Contains(stdout, "info entry") || !strings.Contains(stdout, "error entry") {
+		t.Errorf("expected all entries in stdout, got %q", stdout)
+	}
+	if stderr != "" {
+		t.Errorf("expected nothing in stderr, got %q", stderr)
+	}
+}
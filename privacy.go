@@ -0,0 +1,36 @@
+package logger
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+
+	"go.uber.org/zap"
+)
+
+// HashedID使用的盐，默认为进程启动时生成的随机值
+var hashSalt atomic.Pointer[[]byte]
+
+func init() {
+	salt := make([]byte, 32)
+	_, _ = rand.Read(salt)
+	hashSalt.Store(&salt)
+}
+
+// SetHashSalt 设置HashedID使用的盐，多个进程设置相同的盐时可以跨进程关联同一个用户的日志，
+// 默认使用进程启动时生成的随机盐，只在进程内可关联
+func SetHashSalt(salt string) {
+	b := []byte(salt)
+	hashSalt.Store(&b)
+}
+
+// HashedID 用户id等个人信息类型，输出为加盐的哈希值而不是原始值，相同的值输出相同，日志仍然可以关联
+//
+//	eg: Info("login", HashedID("user_id", userID))
+func HashedID(key string, val string) Field {
+	mac := hmac.New(sha256.New, *hashSalt.Load())
+	mac.Write([]byte(val))
+	return zap.String(key, hex.EncodeToString(mac.Sum(nil)[:16]))
+}
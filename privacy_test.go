@@ -0,0 +1,28 @@
+package logger
+
+import "testing"
+
+func TestHashedID(t *testing.T) {
+	salt := hashSalt.Load()
+	t.Cleanup(func() { hashSalt.Store(salt) })
+
+	a, b := HashedID("user_id", "u-1"), HashedID("user_id", "u-1")
+	if a.String != b.String || a.String == "u-1" || len(a.String) != 32 {
+		t.Errorf("expected stable hashed id, got %q and %q", a.String, b.String)
+	}
+	if c := HashedID("user_id", "u-2"); c.String == a.String {
+		t.Error("expected different ids to have different hashes")
+	}
+
+	SetHashSalt("salt-1")
+	s1 := HashedID("user_id", "u-1").String
+	SetHashSalt("salt-2")
+	s2 := HashedID("user_id", "u-1").String
+	if s1 == s2 || s1 == a.String {
+		t.Error("expected hash to depend on salt")
+	}
+	SetHashSalt("salt-1")
+	if got := HashedID("user_id", "u-1").String; got != s1 {
+		t.Errorf("expected same hash for same salt, got %q and %q", got, s1)
+	}
+}
@@ -0,0 +1,23 @@
+package logger
+
+import (
+	"os"
+	"sync"
+)
+
+var (
+	processInfoOnce sync.Once
+	processInfo     []Field
+)
+
+// 进程信息字段pid和hostname，只在第一次调用时获取，之后重新初始化时复用
+func processInfoFields() []Field {
+	processInfoOnce.Do(func() {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = "unknown"
+		}
+		processInfo = []Field{Int("pid", os.Getpid()), String("hostname", hostname)}
+	})
+	return processInfo
+}
@@ -0,0 +1,39 @@
+// Package protolog 以protojson输出protobuf消息字段，依赖google.golang.org/protobuf，
+// 放在子包中，不输出proto消息的程序不需要引入该依赖
+package protolog
+
+import (
+	"sync/atomic"
+
+	"github.com/zhufuyi/logger"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Proto输出时是否包含默认值字段，由SetEmitDefaults设置
+var emitDefaults atomic.Bool
+
+// SetEmitDefaults 设置Proto字段是否同时输出默认值字段，例如值为0的数字、空字符串，默认不输出
+func SetEmitDefaults(emit bool) {
+	emitDefaults.Store(emit)
+}
+
+// Proto protobuf消息类型，使用protojson序列化，枚举输出为名称，默认不输出默认值字段，
+// 比logger.Any反射内部字段更紧凑和准确，只有日志输出时才序列化，m为nil时输出null
+//
+//	eg: logger.Info("create order", protolog.Proto("req", req))
+func Proto(key string, m proto.Message) logger.Field {
+	if m == nil || !m.ProtoReflect().IsValid() {
+		return zap.Reflect(key, nil)
+	}
+	return zap.Reflect(key, protoMessage{m: m})
+}
+
+type protoMessage struct {
+	m proto.Message
+}
+
+func (p protoMessage) MarshalJSON() ([]byte, error) {
+	return protojson.MarshalOptions{EmitUnpopulated: emitDefaults.Load()}.Marshal(p.m)
+}
@@ -0,0 +1,66 @@
+package protolog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zhufuyi/logger"
+	"google.golang.org/protobuf/types/known/apipb"
+	"google.golang.org/protobuf/types/known/typepb"
+)
+
+// 初始化输出到临时文件的logger，返回读取msg对应日志的函数
+func initTestLogger(t *testing.T) func(msg string) map[string]interface{} {
+	filename := filepath.Join(t.TempDir(), "out.log")
+	if err := logger.Init(logger.WithSave(filename)); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = logger.Close() })
+
+	return func(msg string) map[string]interface{} {
+		f, err := os.Open(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			m := make(map[string]interface{})
+			if json.Unmarshal(scanner.Bytes(), &m) == nil && m["msg"] == msg {
+				return m
+			}
+		}
+		return nil
+	}
+}
+
+func TestProto(t *testing.T) {
+	findLogLine := initTestLogger(t)
+	var nilMethod *apipb.Method
+	logger.Info("proto", Proto("method", &apipb.Method{Name: "Get", Syntax: typepb.Syntax_SYNTAX_EDITIONS}), Proto("nil_method", nilMethod), Proto("nil", nil))
+
+	entry := findLogLine("proto")
+	method, _ := entry["method"].(map[string]interface{})
+	if method["name"] != "Get" || method["syntax"] != "SYNTAX_EDITIONS" {
+		t.Errorf("unexpected proto field %v", entry["method"])
+	}
+	if _, ok := method["requestTypeUrl"]; ok {
+		t.Errorf("expected default fields to be omitted, got %v", method)
+	}
+	for _, key := range []string{"nil_method", "nil"} {
+		if v, ok := entry[key]; !ok || v != nil {
+			t.Errorf("expected %s to be null, got %v", key, entry)
+		}
+	}
+
+	SetEmitDefaults(true)
+	defer SetEmitDefaults(false)
+	logger.Info("proto defaults", Proto("method", &apipb.Method{Name: "Get"}))
+	method, _ = findLogLine("proto defaults")["method"].(map[string]interface{})
+	if v, ok := method["requestTypeUrl"]; !ok || v != "" {
+		t.Errorf("expected default fields to be emitted, got %v", method)
+	}
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 汇总被限流丢弃日志的间隔
+const rateLimitSummaryInterval = time.Second
+
+// 令牌桶限流器，按日志时间补充令牌，同一个logger及With派生的logger共用
+type rateLimiter struct {
+	mu          sync.Mutex
+	perSecond   float64
+	tokens      float64
+	last        time.Time
+	dropped     int64     // 上次汇总后被丢弃的日志数
+	lastSummary time.Time // 上次汇总的时间
+
+	core  zapcore.Core         // 写入汇总日志的core
+	enab  zapcore.LevelEnabler // 全局日志级别，汇总日志与普通日志一样判断级别
+	clock zapcore.Clock
+	stop  chan struct{}
+}
+
+func newRateLimiter(perSecond int, core zapcore.Core, enab zapcore.LevelEnabler, clock zapcore.Clock) *rateLimiter {
+	return &rateLimiter{perSecond: float64(perSecond), tokens: float64(perSecond), core: core, enab: enab, clock: clock}
+}
+
+// 是否允许输出，允许输出且距上次汇总超过汇总间隔时，同时返回需要汇总的丢弃数
+func (l *rateLimiter) allow(now time.Time) (ok bool, dropped int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.last.IsZero() && now.After(l.last) {
+		l.tokens += now.Sub(l.last).Seconds() * l.perSecond
+		if l.tokens > l.perSecond {
+			l.tokens = l.perSecond
+		}
+	}
+	if now.After(l.last) {
+		l.last = now
+	}
+
+	if l.tokens < 1 {
+		l.dropped++
+		return false, 0
+	}
+	l.tokens--
+
+	if l.dropped > 0 && now.Sub(l.lastSummary) >= rateLimitSummaryInterval {
+		dropped = l.takeDropped(now)
+	}
+	return true, dropped
+}
+
+// 取出需要汇总的丢弃数，调用时需要持有锁
+func (l *rateLimiter) takeDropped(now time.Time) int64 {
+	dropped := l.dropped
+	l.dropped = 0
+	l.lastSummary = now
+	return dropped
+}
+
+// 输出被丢弃日志数的汇总，没有被丢弃的日志时不输出
+func (l *rateLimiter) flush() {
+	now := l.clock.Now()
+	l.mu.Lock()
+	dropped := l.takeDropped(now)
+	l.mu.Unlock()
+	l.writeSummary(now, "", dropped)
+}
+
+// 汇总日志是warn级别，先判断全局日志级别，再通过Check写入接受warn级别的输出
+func (l *rateLimiter) writeSummary(now time.Time, loggerName string, dropped int64) {
+	if dropped == 0 || !l.enab.Enabled(zapcore.WarnLevel) {
+		return
+	}
+	summary := zapcore.Entry{Level: zapcore.WarnLevel, Time: now, LoggerName: loggerName, Message: "log rate limit exceeded, entries dropped"}
+	if ce := l.core.Check(summary, nil); ce != nil {
+		ce.Write(Int64("dropped", dropped))
+	}
+}
+
+// 每个汇总间隔输出一次汇总，日志停止后被丢弃的日志数也会输出，调用close停止
+func (l *rateLimiter) start() {
+	l.stop = make(chan struct{})
+	go func() {
+		ticker := l.clock.NewTicker(rateLimitSummaryInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				l.flush()
+			case <-l.stop:
+				return
+			}
+		}
+	}()
+}
+
+// 停止定时汇总，剩余的汇总在关闭logger前的Sync中输出
+func (l *rateLimiter) close() {
+	close(l.stop)
+}
+
+// 限流core，超过每秒日志数上限的日志被丢弃并计入Stats的Dropped，
+// 每个汇总间隔和Sync时输出一条warn级别日志汇总被丢弃的日志数，
+// 先通过被包装core的Check确定要写入的core再取令牌，被采样、输出级别过滤的日志不消耗令牌
+type rateLimitCore struct {
+	zapcore.Core
+	limiter *rateLimiter
+}
+
+func (c *rateLimitCore) With(fields []Field) zapcore.Core {
+	return &rateLimitCore{Core: c.Core.With(fields), limiter: c.limiter}
+}
+
+func (c *rateLimitCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) {
+		return ce
+	}
+
+	inner := c.Core.Check(ent, nil)
+	if inner == nil {
+		return ce
+	}
+
+	ok, dropped := c.limiter.allow(ent.Time)
+	if !ok {
+		countDropped(1)
+		return ce
+	}
+	c.limiter.writeSummary(ent.Time, ent.LoggerName, dropped)
+	return ce.AddCore(ent, &checkedWriter{inner: inner})
+}
+
+func (c *rateLimitCore) Sync() error {
+	c.limiter.flush()
+	return c.Core.Sync()
+}
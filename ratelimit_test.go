@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	clock := &mockClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)}
+	filename := initTestLogger(t, WithRateLimit(5), WithClock(clock))
+	dropped := Stats().Dropped
+
+	for i := 0; i < 10; i++ {
+		Info("burst")
+	}
+	emitted := 0
+	for _, line := range readLogLines(t, filename) {
+		if line["msg"] == "burst" {
+			emitted++
+		}
+	}
+	if emitted == 0 || emitted > 5 {
+		t.Fatalf("expected at most 5 burst entries, got %d", emitted)
+	}
+	got := Stats().Dropped - dropped
+	if got != int64(10-emitted) {
+		t.Errorf("expected %d dropped entries, got %d", 10-emitted, got)
+	}
+
+	// 补充令牌后恢复输出，并汇总被丢弃的日志数
+	clock.Add(time.Second)
+	Info("recovered")
+	lines := readLogLines(t, filename)
+	if findLogLine(lines, "recovered") == nil {
+		t.Error("expected entry after refill")
+	}
+	summary := findLogLine(lines, "log rate limit exceeded, entries dropped")
+	if summary == nil || summary["dropped"] != float64(got) {
+		t.Errorf("expected summary with dropped=%d, got %v", got, summary)
+	}
+}
+
+func TestRateLimitCheckInnerFirst(t *testing.T) {
+	clock := &mockClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)}
+	errBuf := &bytes.Buffer{}
+	filename := initTestLogger(t, WithRateLimit(4), WithClock(clock), WithSampling(time.Minute, 1, 0), WithTeeLevel(errBuf, "error"))
+
+	// 初始化日志消耗一个令牌，被采样丢弃的日志不消耗令牌
+	for i := 0; i < 10; i++ {
+		Info("sampled")
+	}
+	Info("second")
+	Info("third")
+	lines := readLogLines(t, filename)
+	if findLogLine(lines, "second") == nil || findLogLine(lines, "third") == nil {
+		t.Fatalf("expected entries after sampled ones, got %v", lines)
+	}
+
+	// 汇总日志遵循输出的日志级别
+	Info("dropped")
+	clock.Add(time.Second)
+	Info("recovered")
+	if findLogLine(readLogLines(t, filename), "log rate limit exceeded, entries dropped") == nil {
+		t.Error("expected summary in main output")
+	}
+	if strings.Contains(errBuf.String(), "log rate limit exceeded") {
+		t.Errorf("expected no warn summary in error tee, got %q", errBuf.String())
+	}
+}
+
+func TestRateLimitSummaryFlush(t *testing.T) {
+	clock := &mockClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)}
+	filename := initTestLogger(t, WithRateLimit(2), WithClock(clock))
+
+	// 日志停止后Sync时输出汇总
+	for i := 0; i < 5; i++ {
+		Info("storm")
+	}
+	if findLogLine(readLogLines(t, filename), "log rate limit exceeded, entries dropped") != nil {
+		t.Fatal("expected no summary before flush")
+	}
+	if err := Sync(); err != nil {
+		t.Fatal(err)
+	}
+	summary := findLogLine(readLogLines(t, filename), "log rate limit exceeded, entries dropped")
+	if summary == nil || summary["dropped"] != float64(4) {
+		t.Errorf("expected summary with dropped=4 after sync, got %v", summary)
+	}
+}
+
+func TestRateLimitSummaryLevel(t *testing.T) {
+	clock := &mockClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)}
+	filename := initTestLogger(t, WithRateLimit(1), WithClock(clock), WithLogLevel("error"))
+
+	for i := 0; i < 5; i++ {
+		Error("storm")
+	}
+	if err := Sync(); err != nil {
+		t.Fatal(err)
+	}
+	// 汇总日志为warn级别，低于全局日志级别时不输出
+	if summary := findLogLine(readLogLines(t, filename), "log rate limit exceeded, entries dropped"); summary != nil {
+		t.Errorf("expected no warn summary with error level, got %v", summary)
+	}
+}
@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"strings"
+	"sync"
+)
+
+// RingBuffer 在内存中保存最近capacity条日志，可以通过WithTee(rb)与正常输出同时使用，
+// 例如在/debug/logs接口中返回最近的日志，并发安全
+type RingBuffer struct {
+	mu    sync.Mutex
+	lines []string
+	next  int
+	full  bool
+}
+
+// NewRingBuffer 新建RingBuffer，capacity小于1时设置为1
+func NewRingBuffer(capacity int) *RingBuffer {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &RingBuffer{lines: make([]string, capacity)}
+}
+
+// Write 保存一条日志，超过容量时覆盖最旧的日志
+func (rb *RingBuffer) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+
+	rb.mu.Lock()
+	rb.lines[rb.next] = line
+	rb.next++
+	if rb.next == len(rb.lines) {
+		rb.next = 0
+		rb.full = true
+	}
+	rb.mu.Unlock()
+
+	return len(p), nil
+}
+
+// Sync 实现zapcore.WriteSyncer
+func (rb *RingBuffer) Sync() error {
+	return nil
+}
+
+// Dump 返回保存的日志，按从旧到新排序
+func (rb *RingBuffer) Dump() []string {
+	rb.mu.Lock()
+	defer rb.mu.Unlock()
+
+	if !rb.full {
+		return append([]string(nil), rb.lines[:rb.next]...)
+	}
+
+	out := make([]string, 0, len(rb.lines))
+	out = append(out, rb.lines[rb.next:]...)
+	return append(out, rb.lines[:rb.next]...)
+}
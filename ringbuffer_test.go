@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRingBuffer(t *testing.T) {
+	rb := NewRingBuffer(3)
+	if err := Init(WithLogLevel("debug"), WithTee(rb)); err != nil {
+		t.Fatal(err)
+	}
+	defer resetLogger(t)
+
+	for i := 0; i < 5; i++ {
+		Infof("ring buffer line %d", i)
+	}
+
+	lines := rb.Dump()
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		want := fmt.Sprintf("ring buffer line %d", i+2)
+		if !strings.Contains(line, want) {
+			t.Errorf("line %d: expected %q in %q", i, want, line)
+		}
+	}
+}
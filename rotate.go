@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 日志文件的切割方式
+const (
+	RotationSize = "size" // 按文件大小切割
+	RotationTime = "time" // 按时间切割，文件名带日期
+)
+
+const (
+	defaultRotationInterval = 24 * time.Hour
+	defaultRotationPattern  = "2006-01-02"
+	defaultMaxSize          = 100 // MB
+	backupTimeFormat        = "2006-01-02T15-04-05.000"
+)
+
+type rotationOptions struct {
+	mode     string
+	interval time.Duration
+	pattern  string
+	maxSize  int
+}
+
+// 切割日志文件的WriteSyncer
+type rotateWriter struct {
+	mu       sync.Mutex
+	filename string
+	opts     rotationOptions
+	clock    zapcore.Clock
+
+	file *os.File
+	size int64
+	next time.Time // 按时间切割时下一次切割的时间
+}
+
+func newRotateWriter(filename string, opts rotationOptions, clock zapcore.Clock) (*rotateWriter, error) {
+	switch opts.mode {
+	case RotationSize:
+		if opts.maxSize <= 0 {
+			opts.maxSize = defaultMaxSize
+		}
+	case RotationTime:
+		if opts.interval < 0 {
+			return nil, fmt.Errorf("rotation interval must be greater than 0")
+		}
+		if opts.interval == 0 {
+			opts.interval = defaultRotationInterval
+		}
+		if opts.pattern == "" {
+			opts.pattern = defaultRotationPattern
+		}
+	default:
+		return nil, fmt.Errorf("unknown rotation mode %q", opts.mode)
+	}
+
+	w := &rotateWriter{filename: filename, opts: opts, clock: clock}
+	if err := w.rotate(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotateWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.shouldRotate(len(p)) {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+func (w *rotateWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Sync()
+}
+
+func (w *rotateWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
+
+// 当前写入的文件路径
+func (w *rotateWriter) currentName() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Name()
+}
+
+func (w *rotateWriter) shouldRotate(n int) bool {
+	if w.opts.mode == RotationTime {
+		return !w.clock.Now().Before(w.next)
+	}
+	return w.size > 0 && w.size+int64(n) > int64(w.opts.maxSize)*1024*1024
+}
+
+// 关闭当前文件并打开新文件，按大小切割时当前文件重命名为带时间的备份文件
+func (w *rotateWriter) rotate() error {
+	now := w.clock.Now()
+	filename := w.filename
+	if w.opts.mode == RotationTime {
+		start := periodStart(now, w.opts.interval)
+		w.next = start.Add(w.opts.interval)
+		filename = nameWithSuffix(w.filename, start.Format(w.opts.pattern))
+	}
+
+	if w.file != nil {
+		if err := w.file.Close(); err != nil {
+			return err
+		}
+		w.file = nil
+		if w.opts.mode == RotationSize {
+			if err := os.Rename(w.filename, backupName(w.filename, now)); err != nil {
+				return err
+			}
+		}
+	}
+
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return err
+	}
+	w.file, w.size = f, info.Size()
+	return nil
+}
+
+// 时间t所在切割周期的开始时间，周期从当天0点开始计算，大于等于一天的周期从当天0点开始
+func periodStart(t time.Time, interval time.Duration) time.Time {
+	y, m, d := t.Date()
+	midnight := time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+	if interval >= 24*time.Hour {
+		return midnight
+	}
+	return midnight.Add(t.Sub(midnight).Truncate(interval))
+}
+
+// 按大小切割的备份文件名，同一毫秒内多次切割时加上序号，例如：app-2024-06-01T12-00-00.000-1.log
+func backupName(filename string, t time.Time) string {
+	ts := t.Format(backupTimeFormat)
+	name := nameWithSuffix(filename, ts)
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(name); os.IsNotExist(err) {
+			return name
+		}
+		name = nameWithSuffix(filename, ts+"-"+strconv.Itoa(i))
+	}
+}
+
+// 在文件扩展名前加上后缀，例如：app.log --> app-2024-06-01.log
+func nameWithSuffix(filename string, suffix string) string {
+	ext := filepath.Ext(filename)
+	return strings.TrimSuffix(filename, ext) + "-" + suffix + ext
+}
@@ -0,0 +1,126 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+type mockClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *mockClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *mockClock) NewTicker(d time.Duration) *time.Ticker {
+	return time.NewTicker(d)
+}
+
+func (c *mockClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func TestRotationTime(t *testing.T) {
+	clock := &mockClock{now: time.Date(2024, 6, 1, 23, 59, 0, 0, time.Local)}
+	dir := t.TempDir()
+	initTestLogger(t, WithSave(filepath.Join(dir, "app.log")), WithRotationMode(RotationTime), WithClock(clock))
+
+	Info("before midnight")
+	clock.Add(2 * time.Minute)
+	Info("after midnight")
+
+	if entry := findLogLine(readLogLines(t, filepath.Join(dir, "app-2024-06-01.log")), "before midnight"); entry == nil {
+		t.Error("expected entry in app-2024-06-01.log")
+	}
+	entry := findLogLine(readLogLines(t, filepath.Join(dir, "app-2024-06-02.log")), "after midnight")
+	if entry == nil {
+		t.Fatal("expected entry in app-2024-06-02.log")
+	}
+	if ts, _ := entry["ts"].(string); ts[:10] != "2024-06-02" {
+		t.Errorf("expected entry time from clock, got %v", entry["ts"])
+	}
+	if _, err := os.Stat(filepath.Join(dir, "app.log")); !os.IsNotExist(err) {
+		t.Errorf("expected no undated file, got %v", err)
+	}
+}
+
+func TestRotationSize(t *testing.T) {
+	clock := &mockClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)}
+	filename := filepath.Join(t.TempDir(), "app.log")
+	w, err := newRotateWriter(filename, rotationOptions{mode: RotationSize, maxSize: 1}, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chunk := bytes.Repeat([]byte("a"), 600*1024)
+	for i := 0; i < 2; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	info, err := os.Stat(nameWithSuffix(filename, "2024-06-01T12-00-00.000"))
+	if err != nil || info.Size() != int64(len(chunk)) {
+		t.Errorf("expected rotated backup file, got %v, %v", info, err)
+	}
+	info, err = os.Stat(filename)
+	if err != nil || info.Size() != int64(len(chunk)) {
+		t.Errorf("expected new current file, got %v, %v", info, err)
+	}
+}
+
+func TestRotationSizeSameMillisecond(t *testing.T) {
+	clock := &mockClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)}
+	filename := filepath.Join(t.TempDir(), "app.log")
+	w, err := newRotateWriter(filename, rotationOptions{mode: RotationSize, maxSize: 1}, clock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 时钟不变，连续切割两次
+	chunk := bytes.Repeat([]byte("a"), 600*1024)
+	for i := 0; i < 3; i++ {
+		if _, err := w.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, suffix := range []string{"2024-06-01T12-00-00.000", "2024-06-01T12-00-00.000-1"} {
+		if info, err := os.Stat(nameWithSuffix(filename, suffix)); err != nil || info.Size() != int64(len(chunk)) {
+			t.Errorf("expected backup file with suffix %s, got %v, %v", suffix, info, err)
+		}
+	}
+}
+
+func TestRotationModeUnknown(t *testing.T) {
+	if err := Init(WithSave(filepath.Join(t.TempDir(), "app.log")), WithRotationMode("weekly")); err == nil {
+		t.Error("expected error for unknown rotation mode")
+	}
+}
+
+func TestRotationModeInferred(t *testing.T) {
+	initTestLogger(t, WithMaxSize(10))
+	if cfg := EffectiveConfig(); cfg.RotationMode != RotationSize || cfg.RotationMaxSize != 10 {
+		t.Errorf("expected size rotation from WithMaxSize, got %+v", cfg)
+	}
+
+	initTestLogger(t, WithRotationInterval(time.Hour))
+	if cfg := EffectiveConfig(); cfg.RotationMode != RotationTime || cfg.RotationInterval != time.Hour {
+		t.Errorf("expected time rotation from WithRotationInterval, got %+v", cfg)
+	}
+
+	initTestLogger(t, WithMaxSize(10), WithRotationMode(RotationTime))
+	if cfg := EffectiveConfig(); cfg.RotationMode != RotationTime {
+		t.Errorf("expected explicit rotation mode to win, got %+v", cfg)
+	}
+}
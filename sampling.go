@@ -0,0 +1,179 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 当前logger的采样状态，没有设置采样时为nil
+var activeSampling atomic.Pointer[samplingState]
+
+// ResetSampling 清空当前logger的采样计数，相同的日志重新从第一条开始计数，一般用于测试用例之间
+func ResetSampling() {
+	if s := activeSampling.Load(); s != nil {
+		s.reset()
+	}
+}
+
+// 采样状态，同一个logger及With派生的logger共用，重置时替换内部的sampler
+type samplingState struct {
+	opts    samplingOptions
+	sampler atomic.Pointer[zapcore.Core]
+
+	// 按采样key(例如请求id)、级别和消息分别计数，数量达到maxSamplingKeys时先清理过期的计数，
+	// 仍然已满时新的key使用全局采样计数
+	keyMu sync.Mutex
+	keyed map[keyedSample]*sampleCounter
+}
+
+// 按key采样时最多保存的计数数量
+const maxSamplingKeys = 4096
+
+type keyedSample struct {
+	key   string
+	level zapcore.Level
+	msg   string
+}
+
+// 一个tick内的日志计数
+type sampleCounter struct {
+	count int
+	start time.Time
+}
+
+func newSamplingState(opts samplingOptions) *samplingState {
+	s := &samplingState{opts: opts}
+	s.reset()
+	return s
+}
+
+func (s *samplingState) reset() {
+	sampler := s.newSampler()
+	s.sampler.Store(&sampler)
+
+	s.keyMu.Lock()
+	s.keyed = nil
+	s.keyMu.Unlock()
+}
+
+func (s *samplingState) newSampler() zapcore.Core {
+	return zapcore.NewSamplerWithOptions(sampleProbe{}, s.opts.tick, s.opts.first, s.opts.thereafter, zapcore.SamplerHook(s.hook))
+}
+
+// 统计采样结果并调用WithSamplingHooks设置的回调函数
+func (s *samplingState) hook(ent zapcore.Entry, dec zapcore.SamplingDecision) {
+	samplingHook(ent, dec)
+	if dec&zapcore.LogSampled != 0 && s.opts.onSampled != nil {
+		s.opts.onSampled(ent)
+	}
+	if dec&zapcore.LogDropped != 0 && s.opts.onDropped != nil {
+		s.opts.onDropped(ent)
+	}
+}
+
+// 是否采样输出，key不为空时按key分别计数
+func (s *samplingState) sampled(ent zapcore.Entry, key string) bool {
+	if key == "" {
+		return (*s.sampler.Load()).Check(ent, nil) != nil
+	}
+
+	s.keyMu.Lock()
+	n, ok := s.countKeyed(keyedSample{key: key, level: ent.Level, msg: ent.Message}, ent.Time)
+	s.keyMu.Unlock()
+	if !ok {
+		return (*s.sampler.Load()).Check(ent, nil) != nil
+	}
+
+	if n <= s.opts.first || (s.opts.thereafter > 0 && (n-s.opts.first)%s.opts.thereafter == 0) {
+		s.hook(ent, zapcore.LogSampled)
+		return true
+	}
+	s.hook(ent, zapcore.LogDropped)
+	return false
+}
+
+// 增加key的计数并返回当前tick内的计数，计数已满时返回false，调用时需要持有keyMu
+func (s *samplingState) countKeyed(k keyedSample, now time.Time) (int, bool) {
+	if s.keyed == nil {
+		s.keyed = make(map[keyedSample]*sampleCounter)
+	}
+	c, ok := s.keyed[k]
+	if !ok {
+		if len(s.keyed) >= maxSamplingKeys {
+			for key, old := range s.keyed {
+				if now.Sub(old.start) >= s.opts.tick {
+					delete(s.keyed, key)
+				}
+			}
+			if len(s.keyed) >= maxSamplingKeys {
+				return 0, false
+			}
+		}
+		c = &sampleCounter{start: now}
+		s.keyed[k] = c
+	}
+	if now.Sub(c.start) >= s.opts.tick {
+		c.count, c.start = 0, now
+	}
+	c.count++
+	return c.count, true
+}
+
+// 采样core，只判断是否采样，输出由内部的core完成，key为With添加的采样key
+type samplingCore struct {
+	zapcore.Core
+	state *samplingState
+	key   string
+}
+
+func (c *samplingCore) With(fields []Field) zapcore.Core {
+	key := c.key
+	for _, f := range fields {
+		if f.Type == zapcore.SkipType && f.Key == samplingKeyField {
+			key = f.String
+		}
+	}
+	return &samplingCore{Core: c.Core.With(fields), state: c.state, key: key}
+}
+
+func (c *samplingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.Enabled(ent.Level) || !c.state.sampled(ent, c.key) {
+		return ce
+	}
+	return c.Core.Check(ent, ce)
+}
+
+// 传递采样key的字段名，字段类型为SkipType，不会输出
+const samplingKeyField = "__sampling_key"
+
+// WithSamplingKey设置的context key，为空表示不按key采样
+var samplingCtxKey atomic.Value
+
+// 从context读取采样key，返回传递采样key的字段
+func samplingKeyFromContext(ctx context.Context) (Field, bool) {
+	key, _ := samplingCtxKey.Load().(string)
+	if key == "" {
+		return Field{}, false
+	}
+	v := ctx.Value(key)
+	if v == nil {
+		return Field{}, false
+	}
+	return Field{Key: samplingKeyField, Type: zapcore.SkipType, String: fmt.Sprint(v)}, true
+}
+
+// 被sampler包装的探测core，sampler放行时返回非nil的CheckedEntry
+type sampleProbe struct{}
+
+func (sampleProbe) Enabled(zapcore.Level) bool          { return true }
+func (p sampleProbe) With([]zapcore.Field) zapcore.Core { return p }
+func (p sampleProbe) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	return ce.AddCore(ent, p)
+}
+func (sampleProbe) Write(zapcore.Entry, []zapcore.Field) error { return nil }
+func (sampleProbe) Sync() error                                { return nil }
@@ -0,0 +1,107 @@
+package logger
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestResetSampling(t *testing.T) {
+	filename := initTestLogger(t, WithSampling(time.Minute, 1, 0))
+	logger := WithFields(String("case", "reset"))
+	Info("reset sampling")
+	logger.Info("reset sampling")
+
+	ResetSampling()
+	Info("reset sampling")
+	logger.Info("reset sampling")
+
+	count := 0
+	for _, line := range readLogLines(t, filename) {
+		if line["msg"] == "reset sampling" {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("expected 2 entries after reset, got %d", count)
+	}
+}
+
+func TestWithSamplingHooks(t *testing.T) {
+	var sampled, dropped [zapcore.FatalLevel + 1]int
+	initTestLogger(t, WithSampling(time.Minute, 1, 0), WithSamplingHooks(
+		func(ent zapcore.Entry) {
+			if ent.Message == "hooked" {
+				sampled[ent.Level]++
+			}
+		},
+		func(ent zapcore.Entry) { dropped[ent.Level]++ },
+	))
+	for i := 0; i < 3; i++ {
+		Info("hooked")
+		Warn("hooked")
+	}
+
+	if sampled[zapcore.InfoLevel] != 1 || sampled[zapcore.WarnLevel] != 1 {
+		t.Errorf("expected 1 sampled entry per level, got %v", sampled)
+	}
+	if dropped[zapcore.InfoLevel] != 2 || dropped[zapcore.WarnLevel] != 2 {
+		t.Errorf("expected 2 dropped entries per level, got %v", dropped)
+	}
+}
+
+func TestWithSamplingKey(t *testing.T) {
+	filename := initTestLogger(t, WithSampling(time.Minute, 2, 0), WithSamplingKey("request_id"))
+	ctxA := context.WithValue(context.Background(), "request_id", "req-a")
+	ctxB := context.WithValue(context.Background(), "request_id", "req-b")
+
+	for i := 0; i < 5; i++ {
+		Ctx(ctxA).Info("burst", String("rid", "a"))
+	}
+	for i := 0; i < 5; i++ {
+		Ctx(ctxB).Info("burst", String("rid", "b"))
+	}
+	for i := 0; i < 5; i++ {
+		Info("burst", String("rid", "none")) // 没有采样key时使用全局采样计数
+	}
+
+	counts := map[interface{}]int{}
+	for _, line := range readLogLines(t, filename) {
+		if line["msg"] == "burst" {
+			counts[line["rid"]]++
+			if _, ok := line[samplingKeyField]; ok {
+				t.Error("expected sampling key field not to be encoded")
+			}
+		}
+	}
+	if counts["a"] != 2 || counts["b"] != 2 || counts["none"] != 2 {
+		t.Errorf("expected each request to be sampled independently, got %v", counts)
+	}
+}
+
+func TestSamplingKeyLimit(t *testing.T) {
+	s := newSamplingState(samplingOptions{tick: time.Minute, first: 1})
+	now := time.Now()
+	for i := 0; i < maxSamplingKeys+100; i++ {
+		s.sampled(zapcore.Entry{Time: now, Message: "req"}, strconv.Itoa(i))
+	}
+	if len(s.keyed) != maxSamplingKeys {
+		t.Errorf("expected %d keys, got %d", maxSamplingKeys, len(s.keyed))
+	}
+
+	// 超出数量的key使用全局采样计数，first为1时只输出第一条
+	if !s.sampled(zapcore.Entry{Time: now, Message: "overflow"}, "a") || s.sampled(zapcore.Entry{Time: now, Message: "overflow"}, "b") {
+		t.Error("expected keys over the limit to share the global sampler")
+	}
+
+	// 过期的计数被清理
+	if !s.sampled(zapcore.Entry{Time: now.Add(time.Minute), Message: "req"}, "new") {
+		t.Error("expected new key to be sampled after expired counters are evicted")
+	}
+	if len(s.keyed) != 1 {
+		t.Errorf("expected expired keys to be evicted, got %d", len(s.keyed))
+	}
+}
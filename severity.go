@@ -0,0 +1,35 @@
+package logger
+
+import "go.uber.org/zap/zapcore"
+
+// 日志级别对应的syslog数字级别
+func severity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return 2
+	case zapcore.FatalLevel:
+		return 1
+	default:
+		return 6
+	}
+}
+
+// 日志级别输出为数字
+func severityLevelEncoder(level zapcore.Level, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendInt(severity(level))
+}
+
+// 保留文字日志级别时，数字级别作为severity字段输出
+func newSeverityCore(core zapcore.Core) zapcore.Core {
+	return &interceptCore{Core: core, fn: func(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+		return ent, append(fields[:len(fields):len(fields)], Int("severity", severity(ent.Level))), true
+	}}
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestWithSeverity(t *testing.T) {
+	filename := initTestLogger(t, WithSeverity(false))
+	Error("severity error")
+	Warn("severity warn")
+
+	lines := readLogLines(t, filename)
+	entry := findLogLine(lines, "severity error")
+	if entry["severity"] != float64(3) {
+		t.Errorf("expected severity 3, got %v", entry["severity"])
+	}
+	if _, ok := entry["level"]; ok {
+		t.Errorf("unexpected textual level %v", entry["level"])
+	}
+	if entry := findLogLine(lines, "severity warn"); entry["severity"] != float64(4) {
+		t.Errorf("expected severity 4, got %v", entry["severity"])
+	}
+
+	filename = initTestLogger(t, WithSeverity(true))
+	Error("severity with level")
+	entry = findLogLine(readLogLines(t, filename), "severity with level")
+	if entry["severity"] != float64(3) || entry["level"] != "error" {
+		t.Errorf("expected severity and level, got %v", entry)
+	}
+}
+
+func TestSeverityCoreCopiesFields(t *testing.T) {
+	var got []Field
+	core := newSeverityCore(&interceptCore{Core: zapcore.NewNopCore(), fn: func(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+		got = fields
+		return ent, fields, true
+	}})
+
+	fields := make([]Field, 1, 2)
+	fields[0] = String("k", "v")
+	spare := fields[:2]
+	_ = core.Write(zapcore.Entry{Level: zapcore.ErrorLevel}, fields)
+	if len(got) != 2 || spare[1].Key != "" {
+		t.Errorf("expected severity field appended to a copy, got %v, caller slice %v", got, spare)
+	}
+}
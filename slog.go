@@ -0,0 +1,134 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SlogHandler 返回把log/slog日志输出到全局logger的slog.Handler，slog的属性转换为字段，分组转换为嵌套的对象，
+// 调用位置为slog的调用位置，重新初始化全局logger后仍然有效
+//
+//	eg: slog.SetDefault(slog.New(SlogHandler()))
+func SlogHandler() slog.Handler {
+	return &slogHandler{}
+}
+
+type slogHandler struct {
+	fields []Field // WithAttrs添加的字段和WithGroup添加的分组
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return loadLogger().Core().Enabled(zapLevel(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	ce := loadLogger().Check(zapLevel(r.Level), r.Message)
+	if ce == nil {
+		return nil
+	}
+	if !r.Time.IsZero() {
+		ce.Time = r.Time
+	}
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ce.Caller = zapcore.NewEntryCaller(frame.PC, frame.File, frame.Line, true)
+		ce.Caller.Function = frame.Function
+	}
+
+	fields := make([]Field, 0, len(h.fields)+r.NumAttrs())
+	fields = append(fields, h.fields...)
+	r.Attrs(func(a slog.Attr) bool {
+		if f, ok := slogField(a); ok {
+			fields = append(fields, f)
+		}
+		return true
+	})
+	ce.Write(fields...)
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := append([]Field(nil), h.fields...)
+	for _, a := range attrs {
+		if f, ok := slogField(a); ok {
+			fields = append(fields, f)
+		}
+	}
+	return &slogHandler{fields: fields}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{fields: append(h.fields[:len(h.fields):len(h.fields)], zap.Namespace(name))}
+}
+
+// slog日志级别转换为zap日志级别
+func zapLevel(level slog.Level) zapcore.Level {
+	switch {
+	case level < slog.LevelInfo:
+		return zapcore.DebugLevel
+	case level < slog.LevelWarn:
+		return zapcore.InfoLevel
+	case level < slog.LevelError:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+// slog属性转换为字段，空属性返回false
+func slogField(a slog.Attr) (Field, bool) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return Field{}, false
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		return String(a.Key, a.Value.String()), true
+	case slog.KindInt64:
+		return Int64(a.Key, a.Value.Int64()), true
+	case slog.KindUint64:
+		return Uint64(a.Key, a.Value.Uint64()), true
+	case slog.KindFloat64:
+		return Float64(a.Key, a.Value.Float64()), true
+	case slog.KindBool:
+		return Bool(a.Key, a.Value.Bool()), true
+	case slog.KindDuration:
+		return Duration(a.Key, a.Value.Duration()), true
+	case slog.KindTime:
+		return Time(a.Key, a.Value.Time()), true
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return Field{}, false
+		}
+		if a.Key == "" { // 没有key的分组，属性直接添加到上一层
+			return zap.Inline(slogGroup(attrs)), true
+		}
+		return zap.Object(a.Key, slogGroup(attrs)), true
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			return zap.NamedError(a.Key, err), true
+		}
+		return Any(a.Key, a.Value.Any()), true
+	}
+}
+
+// slog分组，输出为对象
+type slogGroup []slog.Attr
+
+func (g slogGroup) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	for _, a := range g {
+		if f, ok := slogField(a); ok {
+			f.AddTo(enc)
+		}
+	}
+	return nil
+}
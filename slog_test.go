@@ -0,0 +1,40 @@
+package logger
+
+import (
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	filename := initTestLogger(t, WithLogLevel("info"))
+	l := slog.New(SlogHandler()).With("service", "api").WithGroup("req")
+	l.Info("slog entry", "id", 7, slog.Group("user", "name", "bob"), "err", errors.New("boom"))
+	l.Debug("slog debug")
+	slog.New(SlogHandler()).Warn("slog warn", slog.Group("", "inline", true))
+
+	lines := readLogLines(t, filename)
+	entry := findLogLine(lines, "slog entry")
+	if entry == nil {
+		t.Fatal("expected slog entry")
+	}
+	if entry["service"] != "api" || entry["level"] != "info" {
+		t.Errorf("unexpected slog entry %v", entry)
+	}
+	req, _ := entry["req"].(map[string]interface{})
+	user, _ := req["user"].(map[string]interface{})
+	if req["id"] != float64(7) || req["err"] != "boom" || user["name"] != "bob" {
+		t.Errorf("expected grouped attributes, got %v", entry["req"])
+	}
+	if caller, _ := entry["caller"].(string); !strings.Contains(caller, "slog_test.go") {
+		t.Errorf("expected caller in slog_test.go, got %v", entry["caller"])
+	}
+
+	if findLogLine(lines, "slog debug") != nil {
+		t.Error("expected debug entry to be filtered by level")
+	}
+	if entry = findLogLine(lines, "slog warn"); entry["level"] != "warn" || entry["inline"] != true {
+		t.Errorf("unexpected warn entry %v", entry)
+	}
+}
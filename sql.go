@@ -0,0 +1,195 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	maxSQLQueryLen = 1024 // sql语句最多输出的字节数
+	maxSQLArgLen   = 64   // 单个参数最多输出的字节数
+)
+
+var (
+	emailRegexp = regexp.MustCompile(`^[^@\s]+@([^@\s]+\.[^@\s]+)$`)
+	spaceRegexp = regexp.MustCompile(`\s+`)
+)
+
+var (
+	slowSQLThreshold atomic.Int64 // 慢sql阈值，单位纳秒
+	sqlRedactor      atomic.Pointer[func(arg interface{}) (interface{}, bool)]
+)
+
+func init() {
+	slowSQLThreshold.Store(int64(200 * time.Millisecond))
+}
+
+// SetSlowSQLThreshold 设置LogSQL的慢sql阈值，耗时超过d时输出warn级别日志，默认为200ms
+func SetSlowSQLThreshold(d time.Duration) {
+	slowSQLThreshold.Store(int64(d))
+}
+
+// RegisterSQLRedactor 注册sql参数的打码函数，fn返回true时用返回值代替原参数输出，
+// 返回false时使用默认的打码规则，fn为nil时取消注册
+//
+//	eg: RegisterSQLRedactor(func(arg interface{}) (interface{}, bool) {
+//		if s, ok := arg.(string); ok && strings.HasPrefix(s, "tok_") {
+//			return "***", true
+//		}
+//		return nil, false
+//	})
+func RegisterSQLRedactor(fn func(arg interface{}) (interface{}, bool)) {
+	if fn == nil {
+		sqlRedactor.Store(nil)
+		return
+	}
+	sqlRedactor.Store(&fn)
+}
+
+// LogSQL 输出sql语句、打码后的参数和耗时elapsed，耗时超过SetSlowSQLThreshold设置的阈值时输出warn级别日志，
+// 否则输出debug级别日志，携带ctx中的链路信息
+//
+//	eg: LogSQL(ctx, "SELECT * FROM user WHERE id = ?", []interface{}{id}, time.Since(start))
+func LogSQL(ctx context.Context, query string, args []interface{}, elapsed time.Duration) {
+	fields := []Field{SQLQuery(query, args...), Duration("elapsed", elapsed)}
+	if elapsed > time.Duration(slowSQLThreshold.Load()) {
+		Ctx(ctx).Warn("slow sql", fields...)
+		return
+	}
+	Ctx(ctx).Debug("sql", fields...)
+}
+
+// SQLQuery sql语句类型，输出为{"query":"...","args":[...]}，query保留占位符，连续空白压缩为一个空格，
+// 超过1024字节时截断，看起来是邮箱、银行卡号的参数被打码，避免日志泄露个人信息，
+// 13~19位且通过Luhn校验的整数参数同样按银行卡号打码
+//
+//	eg: Debug("query user", SQLQuery("SELECT * FROM user WHERE email = ?", email))
+func SQLQuery(query string, args ...interface{}) Field {
+	return zap.Object("sql", sqlQuery{query: query, args: args})
+}
+
+type sqlQuery struct {
+	query string
+	args  []interface{}
+}
+
+func (q sqlQuery) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("query", truncateString(strings.TrimSpace(spaceRegexp.ReplaceAllString(q.query, " ")), maxSQLQueryLen))
+	return enc.AddArray("args", sqlArgs(q.args))
+}
+
+type sqlArgs []interface{}
+
+func (args sqlArgs) MarshalLogArray(arr zapcore.ArrayEncoder) error {
+	redactor := sqlRedactor.Load()
+	for _, arg := range args {
+		if redactor != nil {
+			if v, ok := (*redactor)(arg); ok {
+				arg = v
+			}
+		}
+		switch v := arg.(type) {
+		case nil:
+			arr.AppendString("NULL")
+		case string:
+			arr.AppendString(maskSQLArg(v))
+		case []byte:
+			arr.AppendString(fmt.Sprintf("<%d bytes>", len(v)))
+		case int:
+			appendSQLInt(arr, int64(v))
+		case int32: // 最多10位，不可能是银行卡号
+			arr.AppendInt32(v)
+		case int64:
+			appendSQLInt(arr, v)
+		case uint:
+			appendSQLUint(arr, uint64(v))
+		case uint64:
+			appendSQLUint(arr, v)
+		case float64:
+			arr.AppendFloat64(v)
+		case bool:
+			arr.AppendBool(v)
+		case time.Time:
+			arr.AppendTime(v)
+		default:
+			arr.AppendString(maskSQLArg(fmt.Sprint(v)))
+		}
+	}
+	return nil
+}
+
+// 以银行卡号绑定的整数参数打码后以字符串输出
+func appendSQLInt(arr zapcore.ArrayEncoder, v int64) {
+	if s := strconv.FormatInt(v, 10); isCardNumber(s) {
+		arr.AppendString(maskCardNumber(s))
+		return
+	}
+	arr.AppendInt64(v)
+}
+
+func appendSQLUint(arr zapcore.ArrayEncoder, v uint64) {
+	if s := strconv.FormatUint(v, 10); isCardNumber(s) {
+		arr.AppendString(maskCardNumber(s))
+		return
+	}
+	arr.AppendUint64(v)
+}
+
+// 邮箱只保留域名，银行卡号只保留后4位，其他参数超过64字节时截断
+func maskSQLArg(s string) string {
+	if m := emailRegexp.FindStringSubmatch(s); m != nil {
+		return "***@" + m[1]
+	}
+	if digits := strings.NewReplacer(" ", "", "-", "").Replace(s); isCardNumber(digits) {
+		return maskCardNumber(digits)
+	}
+	return truncateString(s, maxSQLArgLen)
+}
+
+// 13~19位且通过Luhn校验的数字认为是银行卡号
+func isCardNumber(s string) bool {
+	if len(s) < 13 || len(s) > 19 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(s); i++ {
+		c := s[len(s)-1-i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+func maskCardNumber(s string) string {
+	return "****" + s[len(s)-4:]
+}
+
+// 超过max字节时截断，末尾添加"...(N more)"
+func truncateString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	n := max
+	for n > 0 && !utf8.RuneStart(s[n]) { // 不截断多字节字符
+		n--
+	}
+	return s[:n] + fmt.Sprintf("...(%d more)", len(s)-n)
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSQLQuery(t *testing.T) {
+	filename := initTestLogger(t)
+	query := "SELECT *\n\tFROM user WHERE email = ? AND card = ? AND id = ? AND name = ? AND note = ?"
+	Info("sql query", SQLQuery(query, "foo@example.com", "4111-1111-1111-1111", 42, "张三", strings.Repeat("a", 100)))
+
+	entry := findLogLine(readLogLines(t, filename), "sql query")
+	sql, _ := entry["sql"].(map[string]interface{})
+	if sql["query"] != "SELECT * FROM user WHERE email = ? AND card = ? AND id = ? AND name = ? AND note = ?" {
+		t.Errorf("unexpected query %v", sql["query"])
+	}
+	args, _ := sql["args"].([]interface{})
+	if len(args) != 5 {
+		t.Fatalf("unexpected args %v", sql["args"])
+	}
+	if args[0] != "***@example.com" || args[1] != "****1111" || args[2] != float64(42) || args[3] != "张三" {
+		t.Errorf("unexpected masked args %v", args)
+	}
+	if args[4] != strings.Repeat("a", 64)+"...(36 more)" {
+		t.Errorf("expected truncated arg, got %v", args[4])
+	}
+
+	// 以整数绑定的银行卡号同样打码，其他整数不变
+	Info("sql card", SQLQuery("UPDATE card SET no = ? WHERE id = ?", int64(4111111111111111), uint64(4111111111111112), 42))
+	sql, _ = findLogLine(readLogLines(t, filename), "sql card")["sql"].(map[string]interface{})
+	if args, _ := sql["args"].([]interface{}); len(args) != 3 || args[0] != "****1111" || args[1] != float64(4111111111111112) || args[2] != float64(42) {
+		t.Errorf("expected masked integer card number, got %v", sql["args"])
+	}
+
+	long := "SELECT " + strings.Repeat("a", maxSQLQueryLen)
+	if got := truncateString(long, maxSQLQueryLen); !strings.HasSuffix(got, "...(7 more)") {
+		t.Errorf("expected truncated query, got %q", got[len(got)-20:])
+	}
+}
+
+func TestLogSQL(t *testing.T) {
+	filename := initTestLogger(t)
+	SetSlowSQLThreshold(100 * time.Millisecond)
+	defer SetSlowSQLThreshold(200 * time.Millisecond)
+	RegisterSQLRedactor(func(arg interface{}) (interface{}, bool) {
+		if s, ok := arg.(string); ok && strings.HasPrefix(s, "tok_") {
+			return "[redacted]", true
+		}
+		return nil, false
+	})
+	defer RegisterSQLRedactor(nil)
+
+	query := "UPDATE session SET token = ? WHERE id = ?"
+	LogSQL(context.Background(), query, []interface{}{"tok_secret", 1}, 10*time.Millisecond)
+	LogSQL(context.Background(), query, []interface{}{"tok_secret", 2}, 150*time.Millisecond)
+
+	lines := readLogLines(t, filename)
+	fast, slow := findLogLine(lines, "sql"), findLogLine(lines, "slow sql")
+	if fast["level"] != "debug" || slow["level"] != "warn" {
+		t.Errorf("expected debug for fast and warn for slow sql, got %v and %v", fast["level"], slow["level"])
+	}
+	sql, _ := slow["sql"].(map[string]interface{})
+	args, _ := sql["args"].([]interface{})
+	if len(args) != 2 || args[0] != "[redacted]" || args[1] != float64(2) {
+		t.Errorf("expected redacted args, got %v", sql["args"])
+	}
+	if slow["elapsed"] != 0.15 {
+		t.Errorf("expected elapsed 0.15, got %v", slow["elapsed"])
+	}
+	if caller, _ := slow["caller"].(string); !strings.Contains(caller, "sql_test.go") {
+		t.Errorf("expected caller in sql_test.go, got %v", slow["caller"])
+	}
+}
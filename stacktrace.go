@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 总是去掉的堆栈帧，zap和本包的非测试代码
+var stackTrimPrefixes = []string{"go.uber.org/zap", "github.com/zhufuyi/logger."}
+
+// 去掉堆栈中函数名以prefixes开头的帧，zap的堆栈格式为每帧两行：函数名和"\t文件:行号"
+func trimStack(stack string, prefixes []string) string {
+	lines := strings.Split(stack, "\n")
+	kept := make([]string, 0, len(lines))
+	for i := 0; i < len(lines); i += 2 {
+		fn, file := lines[i], ""
+		if i+1 < len(lines) {
+			file = lines[i+1]
+		}
+		if trimFrame(fn, file, prefixes) {
+			continue
+		}
+		kept = append(kept, fn)
+		if i+1 < len(lines) {
+			kept = append(kept, file)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func trimFrame(fn string, file string, prefixes []string) bool {
+	for _, prefix := range stackTrimPrefixes {
+		if strings.HasPrefix(fn, prefix) && !strings.Contains(file, "_test.go:") {
+			return true
+		}
+	}
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(fn, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// 输出前去掉堆栈中的框架帧
+func newStackTrimCore(core zapcore.Core, prefixes []string) zapcore.Core {
+	return &interceptCore{Core: core, fn: func(ent zapcore.Entry, fields []Field) (zapcore.Entry, []Field, bool) {
+		if ent.Stack != "" {
+			ent.Stack = trimStack(ent.Stack, prefixes)
+		}
+		return ent, fields, true
+	}}
+}
@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrimStack(t *testing.T) {
+	stack := strings.Join([]string{
+		"github.com/zhufuyi/logger.Error",
+		"\t/src/logger/logger.go:373",
+		"main.handler",
+		"\t/src/app/main.go:20",
+		"github.com/gin-gonic/gin.(*Context).Next",
+		"\t/go/pkg/mod/github.com/gin-gonic/gin/context.go:174",
+		"net/http.(*conn).serve",
+		"\t/usr/local/go/src/net/http/server.go:2092",
+	}, "\n")
+
+	want := "main.handler\n\t/src/app/main.go:20\nnet/http.(*conn).serve\n\t/usr/local/go/src/net/http/server.go:2092"
+	if got := trimStack(stack, []string{"github.com/gin-gonic/gin"}); got != want {
+		t.Errorf("unexpected trimmed stack:\n%s", got)
+	}
+}
+
+func TestWithStacktraceTrim(t *testing.T) {
+	filename := initTestLogger(t, WithStacktraceTrim("testing."))
+	Error("with stack")
+
+	entry := findLogLine(readLogLines(t, filename), "with stack")
+	stack, _ := entry["stacktrace"].(string)
+	if !strings.Contains(stack, "TestWithStacktraceTrim") {
+		t.Errorf("expected call site in stack, got %q", stack)
+	}
+	if strings.Contains(stack, "testing.tRunner") || strings.Contains(stack, "go.uber.org/zap") {
+		t.Errorf("expected framework frames to be trimmed, got %q", stack)
+	}
+}
@@ -0,0 +1,88 @@
+package logger
+
+import (
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// LoggerStats logger的运行统计
+type LoggerStats struct {
+	Total        int64            // 输出的日志总数
+	Levels       map[string]int64 // 各级别输出的日志数
+	Dropped      int64            // 被丢弃的日志数(采样、溢出等)
+	TimedOut     int64            // 写入超过WithWriteTimeout设置的超时时间、不再等待并在后台继续写入的日志数
+	BytesWritten int64            // 写入输出的字节数
+	LastFlush    time.Time        // 最后一次刷新输出的时间
+}
+
+var stats struct {
+	total     atomic.Int64
+	levels    [zapcore.FatalLevel - zapcore.DebugLevel + 1]atomic.Int64
+	dropped   atomic.Int64
+	timedOut  atomic.Int64
+	bytes     atomic.Int64
+	lastFlush atomic.Int64 // unix纳秒
+}
+
+// Stats 返回进程启动以来logger的运行统计，可以通过debug接口查看logger的状态
+func Stats() LoggerStats {
+	s := LoggerStats{
+		Total:        stats.total.Load(),
+		Levels:       make(map[string]int64, len(stats.levels)),
+		Dropped:      stats.dropped.Load(),
+		TimedOut:     stats.timedOut.Load(),
+		BytesWritten: stats.bytes.Load(),
+	}
+	for i := range stats.levels {
+		s.Levels[(zapcore.DebugLevel + zapcore.Level(i)).String()] = stats.levels[i].Load()
+	}
+	if ns := stats.lastFlush.Load(); ns > 0 {
+		s.LastFlush = time.Unix(0, ns)
+	}
+	return s
+}
+
+// 统计输出的日志，作为zap.Hooks使用
+func countEntry(ent zapcore.Entry) error {
+	stats.total.Add(1)
+	if ent.Level >= zapcore.DebugLevel && ent.Level <= zapcore.FatalLevel {
+		stats.levels[ent.Level-zapcore.DebugLevel].Add(1)
+	}
+	return nil
+}
+
+// 统计被丢弃的日志
+func countDropped(n int64) {
+	stats.dropped.Add(n)
+}
+
+// 统计写入超时的日志
+func countTimedOut(n int64) {
+	stats.timedOut.Add(n)
+}
+
+// 统计采样丢弃的日志
+func samplingHook(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		countDropped(1)
+	}
+}
+
+// 统计写入字节数和刷新时间的WriteSyncer
+type countingWriteSyncer struct {
+	zapcore.WriteSyncer
+}
+
+func (w countingWriteSyncer) Write(p []byte) (int, error) {
+	n, err := w.WriteSyncer.Write(p)
+	stats.bytes.Add(int64(n))
+	return n, err
+}
+
+func (w countingWriteSyncer) Sync() error {
+	err := w.WriteSyncer.Sync()
+	stats.lastFlush.Store(time.Now().UnixNano())
+	return err
+}
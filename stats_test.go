@@ -0,0 +1,38 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	initTestLogger(t, WithSampling(time.Minute, 1, 0))
+	before := Stats()
+
+	Info("stats")
+	Info("stats") // 被采样丢弃
+	Warn("stats warn")
+	if err := Sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	after := Stats()
+	if n := after.Total - before.Total; n != 2 {
+		t.Errorf("expected 2 entries, got %d", n)
+	}
+	if n := after.Levels["info"] - before.Levels["info"]; n != 1 {
+		t.Errorf("expected 1 info entry, got %d", n)
+	}
+	if n := after.Levels["warn"] - before.Levels["warn"]; n != 1 {
+		t.Errorf("expected 1 warn entry, got %d", n)
+	}
+	if n := after.Dropped - before.Dropped; n != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", n)
+	}
+	if after.BytesWritten <= before.BytesWritten {
+		t.Error("expected bytes written to increase")
+	}
+	if after.LastFlush.IsZero() {
+		t.Error("expected last flush time")
+	}
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// 结构体类型的字段信息缓存，key为reflect.Type
+var structFieldsCache sync.Map
+
+type structField struct {
+	index []int
+	name  string
+	mask  bool
+}
+
+// Struct 根据结构体的log标签输出导出字段，字段名为prefix+"_"+名称，prefix为空时为名称，
+// 名称优先使用log标签，其次为json标签和字段名，log:"-"不输出，log:"mask"输出为"***"，
+// v不是结构体时输出为一个字段prefix
+//
+//	eg: type User struct {
+//		Name     string `log:"name"`
+//		Password string `log:"-"`
+//		Phone    string `log:"phone,mask"`
+//	}
+//	Info("create user", Struct("user", user)...)
+func Struct(prefix string, v interface{}) []Field {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return []Field{Any(prefix, v)}
+	}
+
+	sfs := cachedStructFields(rv.Type())
+	fields := make([]Field, 0, len(sfs))
+	for _, sf := range sfs {
+		key := sf.name
+		if prefix != "" {
+			key = prefix + "_" + key
+		}
+		if sf.mask {
+			fields = append(fields, String(key, "***"))
+			continue
+		}
+		fields = append(fields, Any(key, rv.FieldByIndex(sf.index).Interface()))
+	}
+	return fields
+}
+
+func cachedStructFields(t reflect.Type) []structField {
+	if v, ok := structFieldsCache.Load(t); ok {
+		return v.([]structField)
+	}
+
+	var sfs []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" { // 未导出
+			continue
+		}
+
+		sf := structField{index: f.Index, name: f.Name}
+		if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+			sf.name = name
+		}
+		tag, ok := f.Tag.Lookup("log")
+		if tag == "-" {
+			continue
+		}
+		if ok {
+			for i, part := range strings.Split(tag, ",") {
+				switch {
+				case part == "mask":
+					sf.mask = true
+				case i == 0 && part != "":
+					sf.name = part
+				}
+			}
+		}
+		sfs = append(sfs, sf)
+	}
+
+	v, _ := structFieldsCache.LoadOrStore(t, sfs)
+	return v.([]structField)
+}
@@ -0,0 +1,40 @@
+package logger
+
+import "testing"
+
+type structUser struct {
+	ID       int64  `json:"id"`
+	Name     string `log:"name"`
+	Password string `log:"-"`
+	Phone    string `log:"phone,mask"`
+	Email    string `json:"email" log:"mask"`
+	note     string
+}
+
+func TestStruct(t *testing.T) {
+	filename := initTestLogger(t)
+	user := &structUser{ID: 1, Name: "张三", Password: "secret", Phone: "13800000000", Email: "foo@example.com", note: "internal"}
+	Info("struct fields", Struct("user", user)...)
+	Info("struct no prefix", Struct("", *user)...)
+
+	lines := readLogLines(t, filename)
+	entry := findLogLine(lines, "struct fields")
+	if entry["user_id"] != float64(1) || entry["user_name"] != "张三" || entry["user_phone"] != "***" || entry["user_email"] != "***" {
+		t.Errorf("unexpected struct fields %v", entry)
+	}
+	for _, key := range []string{"user_Password", "user_password", "user_note"} {
+		if _, ok := entry[key]; ok {
+			t.Errorf("unexpected field %s", key)
+		}
+	}
+	if entry := findLogLine(lines, "struct no prefix"); entry["name"] != "张三" || entry["id"] != float64(1) {
+		t.Errorf("unexpected struct fields without prefix %v", entry)
+	}
+
+	if fields := Struct("user", (*structUser)(nil)); len(fields) != 0 {
+		t.Errorf("expected no fields for nil pointer, got %v", fields)
+	}
+	if fields := Struct("count", 3); len(fields) != 1 || fields[0].Key != "count" {
+		t.Errorf("expected single field for non-struct, got %v", fields)
+	}
+}
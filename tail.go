@@ -0,0 +1,79 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"strings"
+)
+
+// 从文件末尾每次读取的字节数
+const tailChunkSize = 64 << 10
+
+// Tail 返回当前日志文件最后n行完整的日志，末尾未写完的行被忽略，用于本地调试，
+// 只有输出到文件时可用，否则返回错误，按时间或大小切割时读取当前正在写入的文件
+func Tail(ctx context.Context, n int) ([]string, error) {
+	cfg := EffectiveConfig()
+	if !cfg.IsSave {
+		return nil, errors.New("tail requires the logger to write to a file")
+	}
+	if n <= 0 {
+		return nil, nil
+	}
+
+	filename := cfg.Filename
+	if out := mainOutput.Load(); out != nil {
+		out.mu.RLock()
+		if w, ok := out.ws.(*rotateWriter); ok {
+			filename = w.currentName()
+		}
+		out.mu.RUnlock()
+	}
+	return tailFile(ctx, filename, n)
+}
+
+// 从文件末尾向前按块读取，直到读到n行完整的行
+func tailFile(ctx context.Context, filename string, n int) ([]string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	offset := info.Size()
+	var data []byte
+	for offset > 0 && bytes.Count(data, []byte{'\n'}) <= n {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		size := int64(tailChunkSize)
+		if size > offset {
+			size = offset
+		}
+		offset -= size
+		chunk := make([]byte, size, int(size)+len(data))
+		if _, err := f.ReadAt(chunk, offset); err != nil {
+			return nil, err
+		}
+		data = append(chunk, data...)
+	}
+
+	end := bytes.LastIndexByte(data, '\n')
+	if end < 0 {
+		return nil, nil
+	}
+	lines := strings.Split(string(data[:end]), "\n")
+	if offset > 0 { // 第一行可能不完整
+		lines = lines[1:]
+	}
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return lines, nil
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTail(t *testing.T) {
+	filename := initTestLogger(t)
+	for _, msg := range []string{"one", "two", "three", "four", "five"} {
+		Info(msg)
+	}
+
+	// 末尾未写完的行
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString(`{"level":"info","msg":"parti`)
+	f.Close()
+
+	lines, err := Tail(context.Background(), 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var msgs []string
+	for _, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("expected complete json line, got %q", line)
+		}
+		msgs = append(msgs, entry["msg"].(string))
+	}
+	if strings.Join(msgs, ",") != "three,four,five" {
+		t.Errorf("expected last three entries, got %v", msgs)
+	}
+}
+
+func TestTailFileChunks(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "big.log")
+	line := strings.Repeat("x", 1000)
+	if err := os.WriteFile(filename, []byte(strings.Repeat(line+"\n", 200)+"last\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	lines, err := tailFile(context.Background(), filename, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lines) != 100 || lines[99] != "last" || lines[0] != line {
+		t.Errorf("unexpected tail result, got %d lines", len(lines))
+	}
+}
+
+func TestTailWithoutFile(t *testing.T) {
+	InitNop()
+	defer resetLogger(t)
+
+	if _, err := Tail(context.Background(), 3); err == nil {
+		t.Error("expected error when no file output is configured")
+	}
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// 写入超时的WriteSyncer，写入(包括等待上一次写入完成)超过timeout时不再等待，避免慢输出阻塞请求，
+// 同一时间只有一个写入在进行，保证日志顺序。等待上一次写入超时的日志没有写入，记为丢弃；已经开始写入的日志
+// 在后台继续写入，记入Stats的TimedOut。每次写入需要复制日志内容并启动一个goroutine和timer，只适合用于可能很慢的输出
+type timeoutWriteSyncer struct {
+	ws      zapcore.WriteSyncer
+	timeout time.Duration
+	sem     chan struct{}
+}
+
+func newTimeoutWriteSyncer(ws zapcore.WriteSyncer, timeout time.Duration) *timeoutWriteSyncer {
+	return &timeoutWriteSyncer{ws: ws, timeout: timeout, sem: make(chan struct{}, 1)}
+}
+
+func (w *timeoutWriteSyncer) Write(p []byte) (int, error) {
+	buf := append([]byte(nil), p...) // p会被编码器复用
+	started, timedOut, err := w.do(func() error {
+		_, err := w.ws.Write(buf)
+		return err
+	})
+	if !started {
+		countDropped(1)
+	} else if timedOut {
+		countTimedOut(1)
+	}
+	return len(p), err
+}
+
+func (w *timeoutWriteSyncer) Sync() error {
+	_, _, err := w.do(w.ws.Sync)
+	return err
+}
+
+// 在超时时间内执行fn，started表示fn是否已经开始执行，timedOut表示已经开始的fn超时后在后台继续执行，此时err为nil
+func (w *timeoutWriteSyncer) do(fn func() error) (started, timedOut bool, err error) {
+	timer := time.NewTimer(w.timeout)
+	defer timer.Stop()
+
+	select {
+	case w.sem <- struct{}{}:
+	case <-timer.C:
+		return false, false, nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		err := fn()
+		<-w.sem
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		return true, false, err
+	case <-timer.C:
+		return true, true, nil
+	}
+}
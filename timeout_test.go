@@ -0,0 +1,106 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+// 写入很慢的输出
+type slowWriter struct {
+	delay time.Duration
+}
+
+func (w slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(w.delay)
+	return len(p), nil
+}
+
+func (w slowWriter) Sync() error { return nil }
+
+// 开始写入后阻塞到release关闭的输出
+type blockingWriter struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w blockingWriter) Write(p []byte) (int, error) {
+	w.started <- struct{}{}
+	<-w.release
+	return len(p), nil
+}
+
+func (w blockingWriter) Sync() error { return nil }
+
+func TestTimeoutWriteSyncer(t *testing.T) {
+	ws := newTimeoutWriteSyncer(slowWriter{delay: 200 * time.Millisecond}, 20*time.Millisecond)
+	dropped, timedOut := Stats().Dropped, Stats().TimedOut
+
+	start := time.Now()
+	if _, err := ws.Write([]byte("slow line\n")); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("expected write to return within timeout, took %s", elapsed)
+	}
+	if got := Stats().Dropped - dropped; got != 0 {
+		t.Errorf("expected write still in progress not to be dropped, got %d", got)
+	}
+	if got := Stats().TimedOut - timedOut; got != 1 {
+		t.Errorf("expected 1 timed out write, got %d", got)
+	}
+
+	// 上一次写入还未完成，等待超时后丢弃
+	if _, err := ws.Write([]byte("next line\n")); err != nil {
+		t.Fatal(err)
+	}
+	if got := Stats().Dropped - dropped; got != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", got)
+	}
+
+	// Sync超时不计入丢弃
+	if err := ws.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if got := Stats().Dropped - dropped; got != 1 {
+		t.Errorf("expected sync timeout not to count as dropped, got %d", got)
+	}
+}
+
+func TestTimeoutWriteSyncerBlocked(t *testing.T) {
+	w := blockingWriter{started: make(chan struct{}, 1), release: make(chan struct{})}
+	defer close(w.release)
+	ws := newTimeoutWriteSyncer(w, 20*time.Millisecond)
+	dropped, timedOut := Stats().Dropped, Stats().TimedOut
+
+	// 已经开始的写入阻塞超过超时时间，记为写入超时而不是丢弃
+	if _, err := ws.Write([]byte("blocked line\n")); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-w.started:
+	default:
+		t.Fatal("expected write to have started")
+	}
+	if got := Stats().TimedOut - timedOut; got != 1 {
+		t.Errorf("expected 1 timed out write, got %d", got)
+	}
+	if got := Stats().Dropped - dropped; got != 0 {
+		t.Errorf("expected started write not to be dropped, got %d", got)
+	}
+}
+
+func TestWithWriteTimeout(t *testing.T) {
+	initTestLogger(t, WithWriteTimeout(20*time.Millisecond), WithTee(slowWriter{delay: 200 * time.Millisecond}))
+	time.Sleep(250 * time.Millisecond) // 等待初始化日志写入完成
+	dropped := Stats().Dropped
+
+	start := time.Now()
+	Info("slow tee")
+	Info("slow tee dropped")
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Errorf("expected log calls to return within timeout, took %s", elapsed)
+	}
+	if got := Stats().Dropped - dropped; got != 1 {
+		t.Errorf("expected 1 dropped entry, got %d", got)
+	}
+}